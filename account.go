@@ -3,6 +3,8 @@ package ujeebu
 import (
 	"context"
 	"fmt"
+
+	"github.com/go-resty/resty/v2"
 )
 
 // AccountResponse represents the response from the Ujeebu Account API
@@ -27,23 +29,35 @@ func (c *Client) Account() (*AccountResponse, error) {
 
 // AccountWithContext retrieves account information with context support
 func (c *Client) AccountWithContext(ctx context.Context) (*AccountResponse, error) {
+	account, _, err := c.AccountWithMeta(ctx)
+	return account, err
+}
+
+// AccountWithMeta retrieves account information along with the call metadata
+func (c *Client) AccountWithMeta(ctx context.Context) (*AccountResponse, ResponseMeta, error) {
+	return intercept(ctx, c, "account", any(nil), func(ctx context.Context, _ any) (*AccountResponse, ResponseMeta, error) {
+		return c.accountWithMeta(ctx)
+	})
+}
+
+func (c *Client) accountWithMeta(ctx context.Context) (*AccountResponse, ResponseMeta, error) {
 	req := c.newRequest(ctx)
-	req.SetResult(&AccountResponse{}).SetError(&APIError{})
+	req.SetResult(&AccountResponse{})
 
-	resp, err := req.Get("/account")
+	// The account call is not metered as WithAutoThrottle and the credit budget rely on it
+	resp, meta, err := c.execute(req, apiCall{
+		endpoint:   "account",
+		method:     resty.MethodGet,
+		path:       "/account",
+		idempotent: true,
+	})
 	if err != nil {
-		return nil, &NetworkError{Err: err}
-	}
-
-	if resp.IsError() {
-		apiErr := resp.Error().(*APIError)
-		apiErr.StatusCode = resp.StatusCode()
-		return nil, apiErr
+		return nil, meta, err
 	}
 
 	res := resp.Result()
 	if r, ok := res.(*AccountResponse); ok {
-		return r, nil
+		return r, meta, nil
 	}
-	return nil, fmt.Errorf("account API response is not a valid AccountResponse")
+	return nil, meta, fmt.Errorf("account API response is not a valid AccountResponse")
 }
@@ -0,0 +1,65 @@
+package ujeebu
+
+import "context"
+
+// Extractor is implemented by types calling the Extract API
+type Extractor interface {
+	Extract(params ExtractParams) (*Article, int, error)
+	ExtractWithContext(ctx context.Context, params ExtractParams) (*Article, int, error)
+	ExtractWithMeta(ctx context.Context, params ExtractParams) (*ExtractResponse, ResponseMeta, error)
+}
+
+// Scraper is implemented by types calling the Scrape API
+type Scraper interface {
+	Scrape(params ScrapeParams) (*ScrapeResponse, int, error)
+	ScrapeWithContext(ctx context.Context, params ScrapeParams) (*RawScrapeResponse, int, error)
+	ScrapeWithMeta(ctx context.Context, params ScrapeParams) (*ScrapeResponse, ResponseMeta, error)
+	ScrapeRawWithMeta(ctx context.Context, params ScrapeParams) (*RawScrapeResponse, ResponseMeta, error)
+	Screenshot(params ScrapeParams, fullPage bool, selector string) (string, int, error)
+	PDF(params ScrapeParams) (string, int, error)
+	HTML(params ScrapeParams) (string, int, error)
+	Raw(params ScrapeParams) (string, int, error)
+}
+
+// Carder is implemented by types calling the Card API
+type Carder interface {
+	Card(params CardParams) (*CardResponse, int, error)
+	CardWithContext(ctx context.Context, params CardParams) (*CardResponse, int, error)
+	CardWithMeta(ctx context.Context, params CardParams) (*CardResponse, ResponseMeta, error)
+}
+
+// Searcher is implemented by types calling the SERP API
+type Searcher interface {
+	Serp(params SerpParams) ([]byte, int, error)
+	SerpWithContext(ctx context.Context, params SerpParams) ([]byte, int, error)
+	SerpWithMeta(ctx context.Context, params SerpParams) ([]byte, ResponseMeta, error)
+	GoogleSearch(params SerpParams) (GoogleSearchResult, int, error)
+	GoogleSearchWithContext(ctx context.Context, params SerpParams) (GoogleSearchResult, int, error)
+	GoogleImageSearch(params SerpParams) (GoogleImagesResult, int, error)
+	GoogleImageSearchWithContext(ctx context.Context, params SerpParams) (GoogleImagesResult, int, error)
+	GoogleNewsSearch(params SerpParams) (GoogleNewsResult, int, error)
+	GoogleNewsSearchWithContext(ctx context.Context, params SerpParams) (GoogleNewsResult, int, error)
+	GoogleVideoSearch(params SerpParams) (GoogleVideosResult, int, error)
+	GoogleVideoSearchWithContext(ctx context.Context, params SerpParams) (GoogleVideosResult, int, error)
+	GoogleMapSearch(params SerpParams) (GoogleMapsResult, int, error)
+	GoogleMapSearchWithContext(ctx context.Context, params SerpParams) (GoogleMapsResult, int, error)
+}
+
+// AccountReader is implemented by types calling the Account API
+type AccountReader interface {
+	Account() (*AccountResponse, error)
+	AccountWithContext(ctx context.Context) (*AccountResponse, error)
+	AccountWithMeta(ctx context.Context) (*AccountResponse, ResponseMeta, error)
+}
+
+// API is the full Ujeebu API implemented by *Client.
+// Depend on it, or on the narrower interfaces, to substitute the client in tests.
+type API interface {
+	Extractor
+	Scraper
+	Carder
+	Searcher
+	AccountReader
+}
+
+var _ API = (*Client)(nil)
@@ -0,0 +1,19 @@
+package ujeebu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ImplementsAPI(t *testing.T) {
+	client, err := NewClient("test_api_key")
+	assert.NoError(t, err)
+
+	assert.Implements(t, (*API)(nil), client)
+	assert.Implements(t, (*Extractor)(nil), client)
+	assert.Implements(t, (*Scraper)(nil), client)
+	assert.Implements(t, (*Carder)(nil), client)
+	assert.Implements(t, (*Searcher)(nil), client)
+	assert.Implements(t, (*AccountReader)(nil), client)
+}
@@ -0,0 +1,166 @@
+package ujeebu
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultBatchConcurrency is the number of workers used when BatchOptions.Concurrency is not set
+const DefaultBatchConcurrency = 5
+
+// BatchOptions configures a batch run
+type BatchOptions struct {
+	// Concurrency is the maximum number of calls in flight (DefaultBatchConcurrency if zero)
+	Concurrency int
+	// OnProgress is called after every completed item, never concurrently
+	OnProgress func(BatchProgress)
+}
+
+// BatchProgress reports the progress of a batch run
+type BatchProgress struct {
+	// Completed is the number of items processed so far, including failures
+	Completed int
+	// Total is the number of items in the batch
+	Total int
+	// Failed is the number of items that returned an error
+	Failed int
+	// Credits is the number of credits spent so far
+	Credits int
+}
+
+// BatchResult is the outcome of a single batch item
+type BatchResult[R any] struct {
+	// Index is the position of the item in the input slice
+	Index int
+	// Value is the result of the call, the zero value on error
+	Value R
+	// Meta is the metadata of the call
+	Meta ResponseMeta
+	// Err is the error returned for the item, if any
+	Err error
+}
+
+// BatchReport holds the results of a batch run in input order
+type BatchReport[R any] struct {
+	// Results holds one entry per input item, in input order
+	Results []BatchResult[R]
+	// Credits is the number of credits spent by the batch
+	Credits int
+	// Failed is the number of items that returned an error
+	Failed int
+}
+
+// Errors returns the errors of the failed items, in input order
+func (r *BatchReport[R]) Errors() []error {
+	var errs []error
+	for _, res := range r.Results {
+		if res.Err != nil {
+			errs = append(errs, res.Err)
+		}
+	}
+	return errs
+}
+
+// BatchFunc performs a single call of a batch
+type BatchFunc[P, R any] func(ctx context.Context, params P) (R, ResponseMeta, error)
+
+// StreamBatch calls fn for every item with bounded concurrency and sends the
+// results on the returned channel in completion order. Failed items do not stop
+// the batch; items not started when ctx is done are reported with ctx.Err().
+// The channel is closed once all items are reported and must be drained.
+func StreamBatch[P, R any](ctx context.Context, items []P, opts BatchOptions, fn BatchFunc[P, R]) <-chan BatchResult[R] {
+	out := make(chan BatchResult[R])
+
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = DefaultBatchConcurrency
+	}
+	if workers > len(items) {
+		workers = len(items)
+	}
+
+	jobs := make(chan int)
+	results := make(chan BatchResult[R])
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				res := BatchResult[R]{Index: i}
+				if err := ctx.Err(); err != nil {
+					res.Err = err
+				} else {
+					res.Value, res.Meta, res.Err = fn(ctx, items[i])
+				}
+				results <- res
+			}
+		}()
+	}
+
+	go func() {
+		for i := range items {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	go func() {
+		defer close(out)
+		progress := BatchProgress{Total: len(items)}
+		for res := range results {
+			progress.Completed++
+			progress.Credits += res.Meta.Credits
+			if res.Err != nil {
+				progress.Failed++
+			}
+			if opts.OnProgress != nil {
+				opts.OnProgress(progress)
+			}
+			out <- res
+		}
+	}()
+
+	return out
+}
+
+// RunBatch calls fn for every item with bounded concurrency and returns the results in input order
+func RunBatch[P, R any](ctx context.Context, items []P, opts BatchOptions, fn BatchFunc[P, R]) *BatchReport[R] {
+	report := &BatchReport[R]{Results: make([]BatchResult[R], len(items))}
+	for res := range StreamBatch(ctx, items, opts, fn) {
+		report.Results[res.Index] = res
+		report.Credits += res.Meta.Credits
+		if res.Err != nil {
+			report.Failed++
+		}
+	}
+	return report
+}
+
+// ExtractBatch extracts articles from many URLs with bounded concurrency
+func (c *Client) ExtractBatch(ctx context.Context, params []ExtractParams, opts BatchOptions) *BatchReport[*Article] {
+	return RunBatch(ctx, params, opts, func(ctx context.Context, p ExtractParams) (*Article, ResponseMeta, error) {
+		res, meta, err := c.ExtractWithMeta(ctx, p)
+		if err != nil {
+			return nil, meta, err
+		}
+		return res.Article, meta, nil
+	})
+}
+
+// CardBatch retrieves card information for many URLs with bounded concurrency
+func (c *Client) CardBatch(ctx context.Context, params []CardParams, opts BatchOptions) *BatchReport[*CardResponse] {
+	return RunBatch(ctx, params, opts, c.CardWithMeta)
+}
+
+// ScrapeBatch scrapes many pages with bounded concurrency
+func (c *Client) ScrapeBatch(ctx context.Context, params []ScrapeParams, opts BatchOptions) *BatchReport[*ScrapeResponse] {
+	return RunBatch(ctx, params, opts, c.ScrapeWithMeta)
+}
+
+// SerpBatch runs many searches with bounded concurrency and returns the raw responses
+func (c *Client) SerpBatch(ctx context.Context, params []SerpParams, opts BatchOptions) *BatchReport[[]byte] {
+	return RunBatch(ctx, params, opts, c.SerpWithMeta)
+}
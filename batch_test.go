@@ -0,0 +1,124 @@
+package ujeebu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunBatch_OrderAndPartialFailures(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6}
+
+	var inFlight, peak int32
+	var progress []BatchProgress
+	report := RunBatch(context.Background(), items, BatchOptions{
+		Concurrency: 2,
+		OnProgress:  func(p BatchProgress) { progress = append(progress, p) },
+	}, func(ctx context.Context, n int) (string, ResponseMeta, error) {
+		cur := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if cur <= p || atomic.CompareAndSwapInt32(&peak, p, cur) {
+				break
+			}
+		}
+		// Finish out of order
+		time.Sleep(time.Duration(len(items)-n) * time.Millisecond)
+		if n%3 == 0 {
+			return "", ResponseMeta{Credits: 1}, fmt.Errorf("item %d failed", n)
+		}
+		return fmt.Sprintf("item-%d", n), ResponseMeta{Credits: n}, nil
+	})
+
+	require.Len(t, report.Results, len(items))
+	for i, res := range report.Results {
+		assert.Equal(t, i, res.Index)
+		if items[i]%3 == 0 {
+			assert.Error(t, res.Err)
+		} else {
+			assert.Equal(t, fmt.Sprintf("item-%d", items[i]), res.Value)
+		}
+	}
+	assert.Equal(t, 2, report.Failed)
+	assert.Len(t, report.Errors(), 2)
+	assert.Equal(t, 1+2+1+4+5+1, report.Credits)
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(2))
+
+	require.Len(t, progress, len(items))
+	last := progress[len(progress)-1]
+	assert.Equal(t, BatchProgress{Completed: 6, Total: 6, Failed: 2, Credits: 14}, last)
+}
+
+func TestStreamBatch_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	items := make([]int, 20)
+	var calls int32
+	var results []BatchResult[int]
+	for res := range StreamBatch(ctx, items, BatchOptions{Concurrency: 1}, func(ctx context.Context, n int) (int, ResponseMeta, error) {
+		if atomic.AddInt32(&calls, 1) == 3 {
+			cancel()
+		}
+		return n, ResponseMeta{}, nil
+	}) {
+		results = append(results, res)
+	}
+
+	assert.Len(t, results, len(items), "every item is reported")
+	assert.Less(t, atomic.LoadInt32(&calls), int32(len(items)))
+	assert.True(t, errors.Is(results[len(results)-1].Err, context.Canceled))
+}
+
+func TestRunBatch_Empty(t *testing.T) {
+	report := RunBatch(context.Background(), []int{}, BatchOptions{}, func(ctx context.Context, n int) (int, ResponseMeta, error) {
+		return n, ResponseMeta{}, nil
+	})
+	assert.Empty(t, report.Results)
+	assert.Equal(t, 0, report.Failed)
+}
+
+func TestExtractBatch(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		target := r.URL.Query().Get("url")
+		if strings.HasSuffix(target, "/missing") {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Not found"}`))
+			return
+		}
+		w.Header().Set(CreditsHeader, "10")
+		_, _ = fmt.Fprintf(w, `{"article": {"url": %q, "title": "Title"}}`, target)
+	}))
+	defer mockServer.Close()
+
+	client, err := NewClient("test_api_key", WithBaseURL(mockServer.URL))
+	require.NoError(t, err)
+
+	params := []ExtractParams{
+		{URL: "https://example.com/a"},
+		{URL: "https://example.com/missing"},
+		{URL: "https://example.com/c"},
+	}
+	report := client.ExtractBatch(context.Background(), params, BatchOptions{Concurrency: 3})
+
+	require.Len(t, report.Results, 3)
+	assert.Equal(t, "https://example.com/a", report.Results[0].Value.URL)
+	assert.Equal(t, "https://example.com/c", report.Results[2].Value.URL)
+
+	var apiErr *APIError
+	require.ErrorAs(t, report.Results[1].Err, &apiErr)
+	assert.True(t, apiErr.IsNotFound())
+	assert.Equal(t, 20, report.Credits)
+	assert.Equal(t, 1, report.Failed)
+}
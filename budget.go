@@ -0,0 +1,217 @@
+package ujeebu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrBudgetExceeded is matched by errors.Is when a call is refused by the credit budget
+var ErrBudgetExceeded = errors.New("ujeebu: credit budget exceeded")
+
+// BudgetExceededError is returned when a call is refused because a credit budget is spent
+type BudgetExceededError struct {
+	// Tag is the budget tag that was exhausted, empty for the global budget
+	Tag string
+	// Limit is the configured budget
+	Limit int
+	// Spent is the number of credits spent against the budget
+	Spent int
+}
+
+// Error implements the error interface
+func (e *BudgetExceededError) Error() string {
+	if e.Tag != "" {
+		return fmt.Sprintf("ujeebu: credit budget exceeded for tag '%s' (spent %d of %d)", e.Tag, e.Spent, e.Limit)
+	}
+	return fmt.Sprintf("ujeebu: credit budget exceeded (spent %d of %d)", e.Spent, e.Limit)
+}
+
+// Is makes errors.Is(err, ErrBudgetExceeded) match
+func (e *BudgetExceededError) Is(target error) bool {
+	return target == ErrBudgetExceeded
+}
+
+// CreditBudget configures the credits a client may spend.
+// Budgets are checked before each call, so calls already in flight may overshoot them.
+type CreditBudget struct {
+	// Limit is the maximum number of credits the client may spend, 0 for no limit
+	Limit int
+	// TagLimits caps the credits spent by calls whose context was tagged with WithBudgetTag
+	TagLimits map[string]int
+	// UseAccountBalance additionally caps spending to the account balance,
+	// read from the Account API before the first call
+	UseAccountBalance bool
+}
+
+// Spend is a snapshot of the credits spent by a client
+type Spend struct {
+	// Total is the number of credits spent
+	Total int `json:"total"`
+	// Calls is the number of calls made
+	Calls int `json:"calls"`
+	// ByEndpoint breaks down the credits spent by endpoint name
+	ByEndpoint map[string]int `json:"by_endpoint"`
+	// ByTag breaks down the credits spent by budget tag
+	ByTag map[string]int `json:"by_tag,omitempty"`
+	// Remaining is the account balance left, -1 if the balance is not tracked
+	Remaining int `json:"remaining"`
+}
+
+// WithCreditBudget enforces a credit budget on the client
+func WithCreditBudget(budget CreditBudget) ClientOption {
+	return func(c *Client) {
+		c.ledger = newLedger()
+		c.ledger.budget = budget
+		if budget.UseAccountBalance {
+			c.ledger.fetch = c.AccountWithContext
+		}
+	}
+}
+
+type budgetTagKey struct{}
+
+// WithBudgetTag returns a context whose calls are accounted under tag
+func WithBudgetTag(ctx context.Context, tag string) context.Context {
+	return context.WithValue(ctx, budgetTagKey{}, tag)
+}
+
+func budgetTagFromContext(ctx context.Context) string {
+	tag, _ := ctx.Value(budgetTagKey{}).(string)
+	return tag
+}
+
+// Spend returns a snapshot of the credits spent by the client
+func (c *Client) Spend() Spend {
+	if c.ledger == nil {
+		return Spend{ByEndpoint: map[string]int{}, Remaining: -1}
+	}
+	return c.ledger.snapshot()
+}
+
+// ledger records the credits spent by a client and enforces its budget
+type ledger struct {
+	mu         sync.Mutex
+	budget     CreditBudget
+	total      int
+	calls      int
+	byEndpoint map[string]int
+	byTag      map[string]int
+
+	// Account balance tracking
+	fetch      func(ctx context.Context) (*AccountResponse, error)
+	seeding    chan struct{}
+	seeded     bool
+	balance    int
+	seedCredit int
+}
+
+func newLedger() *ledger {
+	return &ledger{
+		byEndpoint: map[string]int{},
+		byTag:      map[string]int{},
+	}
+}
+
+// check returns a *BudgetExceededError if a call tagged with tag may not be made
+func (l *ledger) check(ctx context.Context, tag string) error {
+	if err := l.seed(ctx); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.budget.Limit > 0 && l.total >= l.budget.Limit {
+		return &BudgetExceededError{Limit: l.budget.Limit, Spent: l.total}
+	}
+	if l.seeded {
+		if spent := l.total - l.seedCredit; spent >= l.balance {
+			return &BudgetExceededError{Limit: l.balance, Spent: spent}
+		}
+	}
+	if tag != "" {
+		if limit, ok := l.budget.TagLimits[tag]; ok && l.byTag[tag] >= limit {
+			return &BudgetExceededError{Tag: tag, Limit: limit, Spent: l.byTag[tag]}
+		}
+	}
+	return nil
+}
+
+// seed reads the account balance once. Concurrent callers wait for the read in flight;
+// if it fails, the next caller reads the balance again.
+func (l *ledger) seed(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if l.fetch == nil || l.seeded {
+			l.mu.Unlock()
+			return nil
+		}
+		if wait := l.seeding; wait != nil {
+			l.mu.Unlock()
+			select {
+			case <-wait:
+				continue
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		done := make(chan struct{})
+		l.seeding = done
+		l.mu.Unlock()
+
+		account, err := l.fetch(ctx)
+
+		l.mu.Lock()
+		l.seeding = nil
+		if err == nil {
+			l.seeded = true
+			l.balance = account.Balance
+			l.seedCredit = l.total
+		}
+		l.mu.Unlock()
+		close(done)
+
+		if err != nil {
+			return fmt.Errorf("failed to read account balance for credit budget: %w", err)
+		}
+		return nil
+	}
+}
+
+// record adds the credits spent by a call
+func (l *ledger) record(endpoint, tag string, credits int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls++
+	l.total += credits
+	l.byEndpoint[endpoint] += credits
+	if tag != "" {
+		l.byTag[tag] += credits
+	}
+}
+
+func (l *ledger) snapshot() Spend {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s := Spend{
+		Total:      l.total,
+		Calls:      l.calls,
+		ByEndpoint: make(map[string]int, len(l.byEndpoint)),
+		ByTag:      make(map[string]int, len(l.byTag)),
+		Remaining:  -1,
+	}
+	for k, v := range l.byEndpoint {
+		s.ByEndpoint[k] = v
+	}
+	for k, v := range l.byTag {
+		s.ByTag[k] = v
+	}
+	if l.seeded {
+		s.Remaining = l.balance - (l.total - l.seedCredit)
+	}
+	return s
+}
@@ -0,0 +1,186 @@
+package ujeebu
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupMockBudgetServer(t *testing.T, credits string) (*httptest.Server, *int32) {
+	var calls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/account":
+			_, _ = w.Write([]byte(`{"balance": 25}`))
+		case "/extract":
+			atomic.AddInt32(&calls, 1)
+			w.Header().Set(CreditsHeader, credits)
+			_, _ = w.Write([]byte(`{"article": {"title": "ok"}}`))
+		default:
+			atomic.AddInt32(&calls, 1)
+			w.Header().Set(CreditsHeader, credits)
+			_, _ = w.Write([]byte(`{"title": "ok"}`))
+		}
+	}))
+	t.Cleanup(mockServer.Close)
+	return mockServer, &calls
+}
+
+func TestCreditBudget_GlobalLimit(t *testing.T) {
+	mockServer, calls := setupMockBudgetServer(t, "10")
+
+	client, err := NewClient("test_api_key", WithBaseURL(mockServer.URL), WithCreditBudget(CreditBudget{Limit: 20}))
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, _, err = client.Card(CardParams{URL: "https://example.com"})
+		require.NoError(t, err)
+	}
+
+	_, _, err = client.Extract(ExtractParams{URL: "https://example.com"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrBudgetExceeded))
+
+	var budgetErr *BudgetExceededError
+	require.ErrorAs(t, err, &budgetErr)
+	assert.Equal(t, 20, budgetErr.Limit)
+	assert.Equal(t, 20, budgetErr.Spent)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestCreditBudget_TagLimit(t *testing.T) {
+	mockServer, _ := setupMockBudgetServer(t, "5")
+
+	client, err := NewClient("test_api_key", WithBaseURL(mockServer.URL), WithCreditBudget(CreditBudget{
+		TagLimits: map[string]int{"nightly": 5},
+	}))
+	require.NoError(t, err)
+
+	ctx := WithBudgetTag(context.Background(), "nightly")
+	_, _, err = client.CardWithContext(ctx, CardParams{URL: "https://example.com"})
+	require.NoError(t, err)
+
+	_, _, err = client.CardWithContext(ctx, CardParams{URL: "https://example.com"})
+	var budgetErr *BudgetExceededError
+	require.ErrorAs(t, err, &budgetErr)
+	assert.Equal(t, "nightly", budgetErr.Tag)
+
+	// Untagged calls are not affected by tag limits
+	_, _, err = client.Card(CardParams{URL: "https://example.com"})
+	assert.NoError(t, err)
+}
+
+func TestCreditBudget_AccountBalance(t *testing.T) {
+	mockServer, _ := setupMockBudgetServer(t, "10")
+
+	client, err := NewClient("test_api_key", WithBaseURL(mockServer.URL), WithCreditBudget(CreditBudget{UseAccountBalance: true}))
+	require.NoError(t, err)
+	assert.Equal(t, -1, client.Spend().Remaining)
+
+	for i := 0; i < 3; i++ {
+		_, _, err = client.Card(CardParams{URL: "https://example.com"})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, -5, client.Spend().Remaining)
+
+	_, _, err = client.Card(CardParams{URL: "https://example.com"})
+	assert.ErrorIs(t, err, ErrBudgetExceeded)
+}
+
+func TestCreditBudget_AccountBalanceSeededOnce(t *testing.T) {
+	var accountCalls int32
+	release := make(chan struct{})
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/account" {
+			// The first read fails, so a waiting caller must read the balance again
+			if atomic.AddInt32(&accountCalls, 1) == 1 {
+				<-release
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"message": "unavailable"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"balance": 100}`))
+			return
+		}
+		w.Header().Set(CreditsHeader, "1")
+		_, _ = w.Write([]byte(`{"title": "ok"}`))
+	}))
+	defer mockServer.Close()
+
+	client, err := NewClient("test_api_key", WithBaseURL(mockServer.URL), WithCreditBudget(CreditBudget{UseAccountBalance: true}))
+	require.NoError(t, err)
+
+	const callers = 10
+	errs := make(chan error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := client.Card(CardParams{URL: "https://example.com"})
+			errs <- err
+		}()
+	}
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&accountCalls) == 1 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	failed := 0
+	for err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&accountCalls))
+	assert.Equal(t, 100-(callers-1), client.Spend().Remaining)
+}
+
+func TestClient_Spend(t *testing.T) {
+	mockServer, _ := setupMockBudgetServer(t, "3")
+
+	client, err := NewClient("test_api_key", WithBaseURL(mockServer.URL))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := client.CardWithContext(WithBudgetTag(context.Background(), "team-a"), CardParams{URL: "https://example.com"})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	_, _, err = client.Extract(ExtractParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	_, err = client.Account()
+	require.NoError(t, err)
+
+	spend := client.Spend()
+	assert.Equal(t, 33, spend.Total)
+	assert.Equal(t, 11, spend.Calls)
+	assert.Equal(t, map[string]int{"card": 30, "extract": 3}, spend.ByEndpoint)
+	assert.Equal(t, map[string]int{"team-a": 30}, spend.ByTag)
+	assert.Equal(t, -1, spend.Remaining)
+}
+
+func TestClient_Spend_ZeroValueClient(t *testing.T) {
+	client := &Client{}
+	spend := client.Spend()
+	assert.Equal(t, 0, spend.Total)
+	assert.Equal(t, -1, spend.Remaining)
+}
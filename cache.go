@@ -0,0 +1,296 @@
+package ujeebu
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCacheTTL is how long responses are cached when no TTL is set for the endpoint
+	DefaultCacheTTL = 24 * time.Hour
+	// DefaultCacheEntries is the capacity of a MemoryCache created with a non-positive size
+	DefaultCacheEntries = 1000
+)
+
+// Cache stores API response bodies by key. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value stored for key, if present and not expired
+	Get(key string) ([]byte, bool)
+	// Set stores value for key; a zero ttl never expires
+	Set(key string, value []byte, ttl time.Duration) error
+	// Delete removes key from the cache
+	Delete(key string) error
+}
+
+// WithCache caches successful Card, Extract and SERP responses in cache.
+// Cache hits are reported with ResponseMeta.Cached set and cost no credits.
+func WithCache(cache Cache) ClientOption {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// WithCacheTTL sets how long responses of endpoint (card, extract, serp) are cached.
+// A negative ttl disables caching for the endpoint, zero caches without expiry.
+func WithCacheTTL(endpoint string, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		if c.cacheTTLs == nil {
+			c.cacheTTLs = map[string]time.Duration{}
+		}
+		c.cacheTTLs[endpoint] = ttl
+	}
+}
+
+type cacheBypassKey struct{}
+
+// WithCacheBypass returns a context whose calls skip cache lookups.
+// Their responses are still stored, refreshing the cached entries.
+func WithCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassKey{}, true)
+}
+
+func cacheBypassed(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	bypass, _ := ctx.Value(cacheBypassKey{}).(bool)
+	return bypass
+}
+
+// cacheTTL returns the TTL of endpoint and whether its responses are cached
+func (c *Client) cacheTTL(endpoint string) (time.Duration, bool) {
+	if c.cache == nil {
+		return 0, false
+	}
+	ttl, ok := c.cacheTTLs[endpoint]
+	if !ok {
+		ttl = DefaultCacheTTL
+	}
+	return ttl, ttl >= 0
+}
+
+// cacheKey returns the canonical hash of a request.
+// url.Values.Encode sorts the parameters, so the key does not depend on their order.
+func (c *Client) cacheKey(endpoint string, query url.Values, headers map[string]string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	h.Write([]byte(c.baseURL + "\n" + endpoint + "\n" + query.Encode() + "\n"))
+	for _, name := range names {
+		h.Write([]byte(strings.ToLower(name) + ":" + headers[name] + "\n"))
+	}
+	return endpoint + "-" + hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheLookup returns the cache key of a request and its cached body, if any.
+// The key is empty when the endpoint is not cached.
+func (c *Client) cacheLookup(ctx context.Context, endpoint string, query url.Values, headers map[string]string) (string, []byte, ResponseMeta, bool) {
+	meta := ResponseMeta{Endpoint: endpoint}
+	if _, ok := c.cacheTTL(endpoint); !ok {
+		return "", nil, meta, false
+	}
+	key := c.cacheKey(endpoint, query, headers)
+	if cacheBypassed(ctx) {
+		return key, nil, meta, false
+	}
+	body, ok := c.cache.Get(key)
+	if !ok {
+		return key, nil, meta, false
+	}
+	meta.Cached = true
+	meta.StatusCode = http.StatusOK
+	return key, body, meta, true
+}
+
+// cacheStore stores the response body of a request looked up with cacheLookup
+func (c *Client) cacheStore(endpoint, key string, body []byte) {
+	if key == "" {
+		return
+	}
+	ttl, _ := c.cacheTTL(endpoint)
+	if err := c.cache.Set(key, body, ttl); err != nil && c.debug && c.logger != nil {
+		c.logger.Printf("ujeebu: caching %s response failed: %v", endpoint, err)
+	}
+}
+
+// MemoryCache is an in-memory Cache evicting the least recently used entries
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List
+	entries    map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache returns a MemoryCache holding up to maxEntries responses (DefaultCacheEntries if not positive)
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheEntries
+	}
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    map[string]*list.Element{},
+	}
+}
+
+// Get implements Cache
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*memoryEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		m.order.Remove(el)
+		delete(m.entries, key)
+		return nil, false
+	}
+	m.order.MoveToFront(el)
+	return entry.value, true
+}
+
+// Set implements Cache
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := &memoryEntry{key: key, value: value}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	if el, ok := m.entries[key]; ok {
+		el.Value = entry
+		m.order.MoveToFront(el)
+		return nil
+	}
+
+	m.entries[key] = m.order.PushFront(entry)
+	for m.order.Len() > m.maxEntries {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryEntry).key)
+	}
+	return nil
+}
+
+// Delete implements Cache
+func (m *MemoryCache) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.entries[key]; ok {
+		m.order.Remove(el)
+		delete(m.entries, key)
+	}
+	return nil
+}
+
+// Len returns the number of entries in the cache, including expired ones not yet evicted
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+// FileCache is a Cache storing one file per entry in a directory.
+// It can be shared between processes and survives restarts.
+type FileCache struct {
+	dir string
+}
+
+// NewFileCache returns a FileCache storing entries in dir, creating it if needed
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileCache{dir: dir}, nil
+}
+
+// path returns the file of key; keys are hashed so any key is a valid file name
+func (f *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(f.dir, name[:2], name)
+}
+
+// Get implements Cache
+func (f *FileCache) Get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(f.path(key))
+	if err != nil || len(data) < 8 {
+		return nil, false
+	}
+	// Entries start with their expiry time in Unix nanoseconds, 0 for none
+	expires := int64(binary.BigEndian.Uint64(data[:8]))
+	if expires != 0 && time.Now().UnixNano() > expires {
+		_ = f.Delete(key)
+		return nil, false
+	}
+	return data[8:], true
+}
+
+// Set implements Cache
+func (f *FileCache) Set(key string, value []byte, ttl time.Duration) error {
+	path := f.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	var expires int64
+	if ttl > 0 {
+		expires = time.Now().Add(ttl).UnixNano()
+	}
+	data := make([]byte, 8, 8+len(value))
+	binary.BigEndian.PutUint64(data, uint64(expires))
+	data = append(data, value...)
+
+	// Write to a temporary file first so readers never see a partial entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Delete implements Cache
+func (f *FileCache) Delete(key string) error {
+	err := os.Remove(f.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,184 @@
+package ujeebu
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupMockCacheServer serves the card, extract and serp endpoints and counts the requests
+func setupMockCacheServer(t *testing.T, opts ...ClientOption) (*Client, *int32) {
+	var hits int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(CreditsHeader, "5")
+		switch r.URL.Path {
+		case "/extract":
+			_, _ = w.Write([]byte(`{"article": {"url": "` + r.URL.Query().Get("url") + `", "title": "Cached"}, "time": 1.5}`))
+		case "/card":
+			_, _ = w.Write([]byte(`{"url": "` + r.URL.Query().Get("url") + `", "title": "Card", "time": 0.5}`))
+		case "/serp":
+			_, _ = w.Write([]byte(`{"organic_results": [{"link": "https://go.dev"}]}`))
+		}
+	}))
+	t.Cleanup(mockServer.Close)
+
+	client, err := NewClient("test_api_key", append([]ClientOption{WithBaseURL(mockServer.URL)}, opts...)...)
+	require.NoError(t, err)
+	return client, &hits
+}
+
+func TestCache_Extract(t *testing.T) {
+	client, hits := setupMockCacheServer(t, WithCache(NewMemoryCache(10)))
+	ctx := context.Background()
+
+	res, meta, err := client.ExtractWithMeta(ctx, ExtractParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.False(t, meta.Cached)
+	assert.Equal(t, 5, meta.Credits)
+
+	res, meta, err = client.ExtractWithMeta(ctx, ExtractParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.True(t, meta.Cached)
+	assert.Equal(t, 0, meta.Credits)
+	assert.Equal(t, 1.5, meta.ServerTime)
+	assert.Equal(t, "Cached", res.Article.Title)
+	assert.Equal(t, int32(1), atomic.LoadInt32(hits))
+
+	// Different params and custom headers are cached separately
+	_, _, err = client.ExtractWithMeta(ctx, ExtractParams{URL: "https://example.com", JS: true})
+	require.NoError(t, err)
+	_, _, err = client.ExtractWithMeta(ctx, ExtractParams{URL: "https://example.com", CustomHeaders: map[string]string{"Cookie": "a=b"}})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(hits))
+
+	spend := client.Spend()
+	assert.Equal(t, 15, spend.Total)
+	assert.Equal(t, 3, spend.Calls)
+}
+
+func TestCache_CardAndSerp(t *testing.T) {
+	client, hits := setupMockCacheServer(t, WithCache(NewMemoryCache(10)))
+
+	for i := 0; i < 2; i++ {
+		card, credits, err := client.Card(CardParams{URL: "https://example.com"})
+		require.NoError(t, err)
+		assert.Equal(t, "Card", card.Title)
+		assert.Equal(t, 5*(1-i), credits)
+
+		result, credits, err := client.GoogleSearch(SerpParams{Search: "golang"})
+		require.NoError(t, err)
+		assert.Equal(t, "https://go.dev", result.OrganicResults[0].Link)
+		assert.Equal(t, 5*(1-i), credits)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(hits))
+}
+
+func TestCache_BypassAndTTL(t *testing.T) {
+	client, hits := setupMockCacheServer(t, WithCache(NewMemoryCache(10)), WithCacheTTL("serp", -1))
+	ctx := context.Background()
+	params := CardParams{URL: "https://example.com"}
+
+	_, _, err := client.CardWithMeta(ctx, params)
+	require.NoError(t, err)
+	_, meta, err := client.CardWithMeta(WithCacheBypass(ctx), params)
+	require.NoError(t, err)
+	assert.False(t, meta.Cached)
+	_, meta, err = client.CardWithMeta(ctx, params)
+	require.NoError(t, err)
+	assert.True(t, meta.Cached)
+	assert.Equal(t, int32(2), atomic.LoadInt32(hits))
+
+	// Caching is disabled for serp
+	for i := 0; i < 2; i++ {
+		_, meta, err := client.SerpWithMeta(ctx, SerpParams{Search: "golang"})
+		require.NoError(t, err)
+		assert.False(t, meta.Cached)
+	}
+	assert.Equal(t, int32(4), atomic.LoadInt32(hits))
+}
+
+func TestCache_ErrorsNotCached(t *testing.T) {
+	var hits int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message": "bad"}`))
+	}))
+	defer mockServer.Close()
+
+	client, err := NewClient("test_api_key", WithBaseURL(mockServer.URL), WithCache(NewMemoryCache(10)))
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, _, err := client.Card(CardParams{URL: "https://example.com"})
+		require.Error(t, err)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&hits))
+}
+
+func TestCacheKey(t *testing.T) {
+	client := &Client{baseURL: DefaultBaseURL}
+	a := url.Values{"url": {"https://example.com"}, "js": {"true"}}
+	b := url.Values{"js": {"true"}, "url": {"https://example.com"}}
+	assert.Equal(t, client.cacheKey("extract", a, nil), client.cacheKey("extract", b, nil))
+	assert.NotEqual(t, client.cacheKey("extract", a, nil), client.cacheKey("card", a, nil))
+	assert.NotEqual(t, client.cacheKey("extract", a, nil), client.cacheKey("extract", a, map[string]string{"Cookie": "x"}))
+}
+
+func TestMemoryCache(t *testing.T) {
+	cache := NewMemoryCache(2)
+	require.NoError(t, cache.Set("a", []byte("1"), 0))
+	require.NoError(t, cache.Set("b", []byte("2"), 0))
+
+	// Reading a makes b the least recently used entry
+	_, ok := cache.Get("a")
+	assert.True(t, ok)
+	require.NoError(t, cache.Set("c", []byte("3"), 0))
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	assert.Equal(t, 2, cache.Len())
+
+	require.NoError(t, cache.Set("d", []byte("4"), time.Millisecond))
+	time.Sleep(5 * time.Millisecond)
+	_, ok = cache.Get("d")
+	assert.False(t, ok)
+
+	require.NoError(t, cache.Delete("c"))
+	_, ok = cache.Get("c")
+	assert.False(t, ok)
+}
+
+func TestFileCache(t *testing.T) {
+	cache, err := NewFileCache(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, cache.Set("key", []byte(`{"a":1}`), 0))
+	value, ok := cache.Get("key")
+	assert.True(t, ok)
+	assert.Equal(t, `{"a":1}`, string(value))
+
+	// Entries survive a new FileCache on the same directory
+	other, err := NewFileCache(cache.dir)
+	require.NoError(t, err)
+	_, ok = other.Get("key")
+	assert.True(t, ok)
+
+	require.NoError(t, cache.Set("short", []byte("x"), time.Millisecond))
+	time.Sleep(5 * time.Millisecond)
+	_, ok = cache.Get("short")
+	assert.False(t, ok)
+
+	require.NoError(t, cache.Delete("key"))
+	require.NoError(t, cache.Delete("key"))
+	_, ok = cache.Get("key")
+	assert.False(t, ok)
+}
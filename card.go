@@ -2,7 +2,10 @@ package ujeebu
 
 import (
 	"context"
+	"encoding/json"
 	"net/url"
+
+	"github.com/go-resty/resty/v2"
 )
 
 // CardParams defines the parameters for the Card API (Article Preview API)
@@ -49,14 +52,38 @@ func (c *Client) Card(params CardParams) (*CardResponse, int, error) {
 
 // CardWithContext retrieves article card/preview information with context support
 func (c *Client) CardWithContext(ctx context.Context, params CardParams) (*CardResponse, int, error) {
+	card, meta, err := c.CardWithMeta(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	return card, meta.Credits, nil
+}
+
+// CardWithMeta retrieves article card/preview information along with the call metadata
+func (c *Client) CardWithMeta(ctx context.Context, params CardParams) (*CardResponse, ResponseMeta, error) {
+	return intercept(ctx, c, "card", params, c.cardWithMeta)
+}
+
+func (c *Client) cardWithMeta(ctx context.Context, params CardParams) (*CardResponse, ResponseMeta, error) {
+	meta := ResponseMeta{Endpoint: "card"}
+
 	// Validate required parameters
 	if params.URL == "" {
-		return nil, 0, &ValidationError{
+		return nil, meta, &ValidationError{
 			Field:   "URL",
 			Message: "URL is required",
 		}
 	}
 
+	cacheKey, cached, cachedMeta, hit := c.cacheLookup(ctx, "card", params.toMap(), params.CustomHeaders)
+	if hit {
+		var card CardResponse
+		if err := json.Unmarshal(cached, &card); err == nil {
+			cachedMeta.ServerTime = card.Time
+			return &card, cachedMeta, nil
+		}
+	}
+
 	req := c.newRequest(ctx)
 
 	// Add custom headers (prefixed with "UJB-")
@@ -64,29 +91,27 @@ func (c *Client) CardWithContext(ctx context.Context, params CardParams) (*CardR
 		req.SetHeader("UJB-"+key, value)
 	}
 
-	// Set up response and error structs
-	req.SetResult(&CardResponse{}).SetError(&APIError{})
+	// Set up response struct
+	req.SetResult(&CardResponse{})
 
 	// Set query parameters
 	req.SetQueryParamsFromValues(params.toMap())
 
 	// Execute GET request
-	resp, err := req.Get("/card")
+	resp, meta, err := c.execute(req, apiCall{
+		endpoint:   "card",
+		method:     resty.MethodGet,
+		path:       "/card",
+		idempotent: true,
+		metered:    true,
+	})
 	if err != nil {
-		return nil, 0, &NetworkError{Err: err}
+		return nil, meta, err
 	}
 
-	// Handle error responses
-	if resp.IsError() {
-		apiErr := resp.Error().(*APIError)
-		apiErr.StatusCode = resp.StatusCode()
-		return nil, 0, apiErr
-	}
-
-	// Extract credits from response header
-	credits := getUjeebuCreditsFromResponse(resp)
-
 	// Return successful response
 	result := resp.Result().(*CardResponse)
-	return result, credits, nil
+	meta.ServerTime = result.Time
+	c.cacheStore("card", cacheKey, resp.Body())
+	return result, meta, nil
 }
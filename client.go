@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"log"
+	"log/slog"
+	"net/http"
 	"os"
 	"time"
 
@@ -26,7 +28,17 @@ type Client struct {
 	client    *resty.Client
 	debug     bool
 	logger    Logger
+	slog      *slog.Logger
 	retryConf *RetryConfig
+	limiter   *limiter
+	ledger    *ledger
+	cache     Cache
+	cacheTTLs map[string]time.Duration
+
+	middlewares []Middleware
+
+	retryPolicy RetryPolicy
+	retryHook   func(RetryEvent)
 }
 
 // Logger is an interface for logging
@@ -62,7 +74,7 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
-// WithDebug enables debug mode with detailed logging
+// WithDebug enables debug mode with detailed logging, secrets are redacted
 func WithDebug(debug bool) ClientOption {
 	return func(c *Client) {
 		c.debug = debug
@@ -77,7 +89,8 @@ func WithLogger(logger Logger) ClientOption {
 	}
 }
 
-// WithRetry configures retry behavior for failed requests
+// WithRetry configures retry behavior for failed requests.
+// It installs a BackoffPolicy retrying network errors, 408, 429 and 5xx responses.
 func WithRetry(maxRetries int, waitTime, maxWaitTime time.Duration) ClientOption {
 	return func(c *Client) {
 		c.retryConf = &RetryConfig{
@@ -85,10 +98,11 @@ func WithRetry(maxRetries int, waitTime, maxWaitTime time.Duration) ClientOption
 			WaitTime:    waitTime,
 			MaxWaitTime: maxWaitTime,
 		}
-		c.client.
-			SetRetryCount(maxRetries).
-			SetRetryWaitTime(waitTime).
-			SetRetryMaxWaitTime(maxWaitTime)
+		c.retryPolicy = &BackoffPolicy{
+			MaxAttempts: maxRetries + 1,
+			BaseDelay:   waitTime,
+			MaxDelay:    maxWaitTime,
+		}
 	}
 }
 
@@ -99,6 +113,13 @@ func WithUserAgent(userAgent string) ClientOption {
 	}
 }
 
+// WithTransport sets the http.RoundTripper used to send requests
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.client.SetTransport(transport)
+	}
+}
+
 // NewClient creates a new Ujeebu API client with the provided API key and options
 func NewClient(apiKey string, opts ...ClientOption) (*Client, error) {
 	if apiKey == "" {
@@ -121,12 +142,20 @@ func NewClient(apiKey string, opts ...ClientOption) (*Client, error) {
 			SetHeader("User-Agent", DefaultUserAgent).
 			SetTimeout(DefaultTimeout),
 		logger: log.Default(),
+		ledger: newLedger(),
 	}
 
 	// Apply options
 	for _, opt := range opts {
 		opt(client)
 	}
+	if client.limiter != nil {
+		client.limiter.logger = client.logger
+	}
+	client.client.
+		SetLogger(restyLogger{c: client}).
+		OnRequestLog(redactRequestLog).
+		OnResponseLog(redactResponseLog)
 
 	return client, nil
 }
@@ -159,5 +188,130 @@ func (c *Client) newRequest(ctx context.Context) *resty.Request {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return c.client.R().SetContext(ctx)
+	req := c.client.R().SetContext(ctx)
+	if key := apiKeyFromContext(ctx); key != "" {
+		req.SetHeader("ApiKey", key)
+	}
+	return req
+}
+
+// apiCall describes an API call sent through execute
+type apiCall struct {
+	endpoint   string
+	method     string
+	path       string
+	idempotent bool
+	// metered calls are rate limited and count against the credit budget
+	metered bool
+}
+
+// execute sends req after checking the credit budget and records the credits spent.
+// Errors are returned as *APIError, *NetworkError or *BudgetExceededError.
+func (c *Client) execute(req *resty.Request, call apiCall) (*resty.Response, ResponseMeta, error) {
+	if !call.metered || c.ledger == nil {
+		return c.send(req, call)
+	}
+
+	tag := budgetTagFromContext(req.Context())
+	if err := c.ledger.check(req.Context(), tag); err != nil {
+		return nil, ResponseMeta{Endpoint: call.endpoint}, err
+	}
+
+	resp, meta, err := c.send(req, call)
+	c.ledger.record(call.endpoint, tag, meta.Credits)
+	return resp, meta, err
+}
+
+// send sends req, retrying according to the client's retry policy
+func (c *Client) send(req *resty.Request, call apiCall) (*resty.Response, ResponseMeta, error) {
+	ctx := req.Context()
+	start := time.Now()
+	meta := ResponseMeta{Endpoint: call.endpoint}
+
+	for attempt := 1; ; attempt++ {
+		c.logRequest(ctx, req, call, attempt)
+		resp, err := c.attempt(req, call)
+		meta.Attempts = attempt
+		meta.Duration = time.Since(start)
+		meta.fill(resp)
+
+		if err == nil {
+			c.logAttempt(ctx, req, call, attempt, meta, nil, false, 0)
+			c.notifyRetry(RetryEvent{RetryAttempt: RetryAttempt{
+				Endpoint:   call.endpoint,
+				Attempt:    attempt,
+				StatusCode: resp.StatusCode(),
+				Elapsed:    meta.Duration,
+				Idempotent: call.idempotent,
+			}})
+			return resp, meta, nil
+		}
+
+		info := RetryAttempt{
+			Endpoint:   call.endpoint,
+			Attempt:    attempt,
+			Err:        err,
+			StatusCode: meta.StatusCode,
+			Elapsed:    meta.Duration,
+			Idempotent: call.idempotent,
+		}
+		if meta.Headers != nil {
+			info.RetryAfter = parseRetryAfter(meta.Headers.Get("Retry-After"))
+		}
+
+		// A response that was received but could not be decoded is not retried
+		var netErr *NetworkError
+		received := errors.As(err, &netErr) && info.StatusCode != 0
+
+		var delay time.Duration
+		retry := false
+		if c.retryPolicy != nil && ctx.Err() == nil && !received {
+			delay, retry = c.retryPolicy.Retry(info)
+		}
+		if retry {
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+				retry = false
+			}
+		}
+		c.logAttempt(ctx, req, call, attempt, meta, err, retry, delay)
+		c.notifyRetry(RetryEvent{RetryAttempt: info, Retrying: retry, Delay: delay})
+
+		if !retry {
+			return resp, meta, err
+		}
+		if sleepErr := sleepContext(ctx, delay); sleepErr != nil {
+			meta.Duration = time.Since(start)
+			return resp, meta, err
+		}
+	}
+}
+
+// attempt sends req once, waiting on the rate limiter if needed
+func (c *Client) attempt(req *resty.Request, call apiCall) (*resty.Response, error) {
+	if call.metered && c.limiter != nil {
+		release, err := c.limiter.acquire(req.Context())
+		if err != nil {
+			return nil, &NetworkError{Err: err}
+		}
+		defer release()
+	}
+
+	req.SetError(&APIError{})
+	resp, err := req.Execute(call.method, call.path)
+	if err != nil {
+		return resp, &NetworkError{Err: err}
+	}
+
+	if resp.IsError() {
+		apiErr := resp.Error().(*APIError)
+		apiErr.StatusCode = resp.StatusCode()
+		return resp, apiErr
+	}
+	return resp, nil
+}
+
+func (c *Client) notifyRetry(event RetryEvent) {
+	if c.retryHook != nil {
+		c.retryHook(event)
+	}
 }
@@ -0,0 +1,374 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ujeebu/ujeebu-go"
+)
+
+// maxJobLine is the maximum size of a job line
+const maxJobLine = 16 << 20
+
+// job is a line of the batch input
+type job struct {
+	// ID identifies the job in the results, the line number if empty
+	ID       string          `json:"id"`
+	Endpoint string          `json:"endpoint"`
+	Params   json.RawMessage `json:"params"`
+	// Headers are the custom UJB- headers of the call
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// err is set when the line is not a valid job
+	err error
+}
+
+// invalidJobError is returned for lines that are not valid jobs
+type invalidJobError struct {
+	line int
+	msg  string
+}
+
+func (e *invalidJobError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.line, e.msg)
+}
+
+// jobResult is a line of the batch output
+type jobResult struct {
+	ID       string    `json:"id"`
+	Endpoint string    `json:"endpoint,omitempty"`
+	Status   string    `json:"status"`
+	Result   any       `json:"result,omitempty"`
+	Encoding string    `json:"encoding,omitempty"`
+	Error    *jobError `json:"error,omitempty"`
+	Credits  int       `json:"credits"`
+	Duration int64     `json:"duration_ms"`
+}
+
+// jobError describes the error of a failed job
+type jobError struct {
+	// Code is the APIError code, or the error class for other errors
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"status_code,omitempty"`
+}
+
+// batchSummary is printed once the batch is done
+type batchSummary struct {
+	Jobs           int            `json:"jobs"`
+	Skipped        int            `json:"skipped"`
+	Succeeded      int            `json:"succeeded"`
+	Failed         int            `json:"failed"`
+	Canceled       int            `json:"canceled,omitempty"`
+	Credits        int            `json:"credits"`
+	FailuresByCode map[string]int `json:"failures_by_code,omitempty"`
+	Duration       string         `json:"duration"`
+}
+
+// Job statuses
+const (
+	statusOK    = "ok"
+	statusError = "error"
+)
+
+// runBatch runs the jobs of a JSONL file and appends their results to the -o file, or
+// writes them to stdout. Jobs whose ID is already in the -o file are skipped.
+func runBatch(e *env, args []string) error {
+	fs, opts := e.flagSet("batch", "[jobs.jsonl | -]")
+	fs.Lookup("format").DefValue = formatTable
+	opts.format = formatTable
+	concurrency := fs.Int("concurrency", ujeebu.DefaultBatchConcurrency, "number of jobs run in parallel")
+	rate := fs.Float64("rate", 0, "maximum number of requests per second, 0 for no limit")
+	rest, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 1 {
+		return usagef("expected a single jobs file, got %d arguments", len(rest))
+	}
+	if *concurrency <= 0 {
+		return usagef("--concurrency must be positive")
+	}
+
+	input := e.stdin
+	if len(rest) == 1 && rest[0] != "-" {
+		f, err := os.Open(rest[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		input = f
+	}
+	jobs, err := readJobs(input)
+	if err != nil {
+		return err
+	}
+
+	out := e.stdout
+	done := map[string]bool{}
+	if opts.output != "" {
+		if done, err = completedJobs(opts.output); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(opts.output, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := terminateLastLine(f, opts.output); err != nil {
+			return err
+		}
+		out = f
+	}
+
+	summary := batchSummary{Jobs: len(jobs), FailuresByCode: map[string]int{}}
+	var pending []job
+	for _, j := range jobs {
+		if done[j.ID] {
+			summary.Skipped++
+			continue
+		}
+		pending = append(pending, j)
+	}
+
+	var clientOpts []ujeebu.ClientOption
+	if *rate > 0 {
+		clientOpts = append(clientOpts, ujeebu.WithRateLimit(*rate, 0))
+	}
+	client, err := e.client(opts, clientOpts...)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	batchOpts := ujeebu.BatchOptions{Concurrency: *concurrency}
+	for res := range ujeebu.StreamBatch(e.ctx, pending, batchOpts, func(ctx context.Context, j job) (jobResult, ujeebu.ResponseMeta, error) {
+		return runJob(ctx, client, j)
+	}) {
+		// Jobs interrupted before completion are left out of the results to be run on resume
+		if res.Err != nil && e.ctx.Err() != nil && errors.Is(res.Err, e.ctx.Err()) {
+			summary.Canceled++
+			continue
+		}
+
+		result := res.Value
+		result.ID, result.Endpoint = pending[res.Index].ID, pending[res.Index].Endpoint
+		result.Credits = res.Meta.Credits
+		result.Duration = res.Meta.Duration.Milliseconds()
+		summary.Credits += res.Meta.Credits
+		if res.Err != nil {
+			result.Status, result.Result, result.Encoding = statusError, nil, ""
+			result.Error = newJobError(res.Err)
+			summary.Failed++
+			summary.FailuresByCode[result.Error.Code]++
+		} else {
+			result.Status = statusOK
+			summary.Succeeded++
+		}
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+		if opts.verbose {
+			fmt.Fprintf(e.stderr, "%s %s credits=%d\n", result.ID, result.Status, result.Credits)
+		}
+	}
+	summary.Duration = time.Since(start).Round(time.Millisecond).String()
+
+	if err := writeOutput(e.stderr, opts.format, summary); err != nil {
+		return err
+	}
+	switch {
+	case summary.Canceled > 0:
+		return fmt.Errorf("batch interrupted, %d jobs not run", summary.Canceled)
+	case summary.Failed > 0:
+		return fmt.Errorf("%d of %d jobs failed", summary.Failed, summary.Failed+summary.Succeeded)
+	}
+	return nil
+}
+
+// readJobs reads one job per line, skipping blank lines.
+// Invalid lines are returned as jobs with an error so that they are reported in the results.
+func readJobs(r io.Reader) ([]job, error) {
+	var jobs []job
+	ids := map[string]bool{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxJobLine)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var j job
+		if err := json.Unmarshal(data, &j); err != nil {
+			j = job{err: &invalidJobError{line: line, msg: "invalid job: " + err.Error()}}
+		}
+		if j.ID == "" {
+			j.ID = strconv.Itoa(line)
+		}
+		if ids[j.ID] {
+			j.err = &invalidJobError{line: line, msg: fmt.Sprintf("duplicate job id %q", j.ID)}
+			j.ID = fmt.Sprintf("%s#%d", j.ID, line)
+		}
+		ids[j.ID] = true
+		jobs = append(jobs, j)
+	}
+	return jobs, scanner.Err()
+}
+
+// completedJobs returns the IDs of the jobs in an existing results file
+func completedJobs(path string) (map[string]bool, error) {
+	done := map[string]bool{}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxJobLine)
+	for scanner.Scan() {
+		var res struct {
+			ID string `json:"id"`
+		}
+		// Lines truncated by an interrupted run are ignored
+		if json.Unmarshal(scanner.Bytes(), &res) == nil && res.ID != "" {
+			done[res.ID] = true
+		}
+	}
+	return done, scanner.Err()
+}
+
+// terminateLastLine appends a newline to f when its last line, at path, was truncated by an interrupted run
+func terminateLastLine(f *os.File, path string) error {
+	r, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	info, err := r.Stat()
+	if err != nil || info.Size() == 0 {
+		return err
+	}
+	last := make([]byte, 1)
+	if _, err := r.ReadAt(last, info.Size()-1); err != nil {
+		return err
+	}
+	if last[0] != '\n' {
+		_, err = f.Write([]byte{'\n'})
+	}
+	return err
+}
+
+// runJob calls the endpoint of j
+func runJob(ctx context.Context, client *ujeebu.Client, j job) (jobResult, ujeebu.ResponseMeta, error) {
+	var res jobResult
+	meta := ujeebu.ResponseMeta{Endpoint: j.Endpoint}
+	if j.err != nil {
+		return res, meta, j.err
+	}
+
+	var err error
+	switch j.Endpoint {
+	case "extract":
+		var params ujeebu.ExtractParams
+		if err = decodeParams(j, &params); err == nil {
+			params.CustomHeaders = j.Headers
+			res.Result, meta, err = client.ExtractWithMeta(ctx, params)
+		}
+	case "card":
+		var params ujeebu.CardParams
+		if err = decodeParams(j, &params); err == nil {
+			params.CustomHeaders = j.Headers
+			res.Result, meta, err = client.CardWithMeta(ctx, params)
+		}
+	case "scrape":
+		var params ujeebu.ScrapeParams
+		if err = decodeParams(j, &params); err == nil {
+			params.CustomHeaders = j.Headers
+			var raw *ujeebu.RawScrapeResponse
+			if raw, meta, err = client.ScrapeRawWithMeta(ctx, params); err == nil {
+				res.Result, res.Encoding = encodeBody(raw.Body)
+			}
+		}
+	case "serp":
+		var params ujeebu.SerpParams
+		if err = decodeParams(j, &params); err == nil {
+			var body []byte
+			if body, meta, err = client.SerpWithMeta(ctx, params); err == nil {
+				res.Result, res.Encoding = encodeBody(body)
+			}
+		}
+	default:
+		err = &ujeebu.ValidationError{Field: "endpoint", Message: fmt.Sprintf("unknown endpoint %q, expected extract, scrape, card or serp", j.Endpoint)}
+	}
+	return res, meta, err
+}
+
+// decodeParams decodes the params of j, rejecting unknown fields
+func decodeParams(j job, params any) error {
+	if len(j.Params) == 0 {
+		return &ujeebu.ValidationError{Field: "params", Message: "params are required"}
+	}
+	dec := json.NewDecoder(bytes.NewReader(j.Params))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(params); err != nil {
+		return &ujeebu.ValidationError{Field: "params", Message: err.Error()}
+	}
+	return nil
+}
+
+// encodeBody returns a response body as JSON, text, or base64 for binary bodies
+func encodeBody(body []byte) (any, string) {
+	switch {
+	case json.Valid(body):
+		return json.RawMessage(body), ""
+	case utf8.Valid(body):
+		return string(body), ""
+	default:
+		return base64.StdEncoding.EncodeToString(body), "base64"
+	}
+}
+
+// newJobError describes err, classified by APIError code
+func newJobError(err error) *jobError {
+	je := &jobError{Message: err.Error()}
+	var apiErr *ujeebu.APIError
+	var validationErr *ujeebu.ValidationError
+	var networkErr *ujeebu.NetworkError
+	var invalidErr *invalidJobError
+	switch {
+	case errors.As(err, &apiErr):
+		je.StatusCode = apiErr.StatusCode
+		je.Message = apiErr.Message
+		je.Code = strconv.Itoa(apiErr.StatusCode)
+		if apiErr.ErrorCode != nil {
+			je.Code = strings.TrimSpace(fmt.Sprint(apiErr.ErrorCode))
+		}
+	case errors.As(err, &validationErr):
+		je.Code = "validation"
+	case errors.As(err, &networkErr):
+		je.Code = "network"
+	case errors.As(err, &invalidErr):
+		je.Code = "invalid_job"
+	default:
+		je.Code = "error"
+	}
+	return je
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ujeebu/ujeebu-go/ujeebutest"
+)
+
+const batchJobs = `{"id": "a", "endpoint": "card", "params": {"url": "https://example.com/a"}}
+{"id": "b", "endpoint": "extract", "params": {"url": "https://example.com/b", "js": true}, "headers": {"Accept-Language": "fr"}}
+
+{"id": "c", "endpoint": "serp", "params": {"search": "golang"}}
+{"id": "d", "endpoint": "scrape", "params": {"url": "https://example.com/d", "response_type": "screenshot"}}
+`
+
+// runBatchCLI runs the batch command with jobs on stdin
+func runBatchCLI(server *ujeebutest.Server, jobs string, args ...string) result {
+	var stdout, stderr bytes.Buffer
+	vars := map[string]string{"UJEEBU_API_KEY": ujeebutest.TestAPIKey, "UJEEBU_BASE_URL": server.URL}
+	code := run(context.Background(), append([]string{"batch"}, args...), strings.NewReader(jobs), &stdout, &stderr, func(name string) string { return vars[name] })
+	return result{code: code, stdout: stdout.String(), stderr: stderr.String()}
+}
+
+func decodeResults(t *testing.T, data string) map[string]jobResult {
+	results := map[string]jobResult{}
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		var res jobResult
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &res))
+		results[res.ID] = res
+	}
+	return results
+}
+
+func TestBatch_Run(t *testing.T) {
+	server := ujeebutest.NewServer(t)
+
+	res := runBatchCLI(server, batchJobs, "--concurrency", "2", "--format", "json")
+	require.Equal(t, exitOK, res.code, res.stderr)
+
+	results := decodeResults(t, res.stdout)
+	require.Len(t, results, 4)
+	for _, id := range []string{"a", "b", "c", "d"} {
+		assert.Equal(t, statusOK, results[id].Status, id)
+	}
+	assert.Equal(t, "card", results["a"].Endpoint)
+	assert.Equal(t, "https://example.com/a", results["a"].Result.(map[string]any)["url"])
+	assert.Equal(t, "base64", results["d"].Encoding)
+	assert.Equal(t, ujeebutest.DefaultCredits["serp"], results["c"].Credits)
+
+	req, ok := server.LastRequest("extract")
+	require.True(t, ok)
+	assert.Equal(t, "fr", req.Header.Get("UJB-Accept-Language"))
+
+	var summary batchSummary
+	require.NoError(t, json.Unmarshal([]byte(res.stderr), &summary))
+	assert.Equal(t, 4, summary.Jobs)
+	assert.Equal(t, 4, summary.Succeeded)
+	assert.Equal(t, ujeebutest.DefaultCredits["card"]+ujeebutest.DefaultCredits["extract"]+
+		ujeebutest.DefaultCredits["serp"]+ujeebutest.DefaultCredits["scrape"], summary.Credits)
+}
+
+func TestBatch_Failures(t *testing.T) {
+	server := ujeebutest.NewServer(t)
+	server.Fail("card", ujeebutest.Fault{StatusCode: http.StatusNotFound, ErrorCode: "NOT_FOUND", Message: "not found", Times: 1})
+	jobs := `{"id": "missing", "endpoint": "card", "params": {"url": "https://example.com/missing"}}
+{"id": "typo", "endpoint": "card", "params": {"ulr": "https://example.com"}}
+{"id": "unknown", "endpoint": "crawl", "params": {}}
+not json
+{"id": "ok", "endpoint": "card", "params": {"url": "https://example.com"}}
+{"id": "ok", "endpoint": "card", "params": {"url": "https://example.com"}}
+`
+	res := runBatchCLI(server, jobs, "--format", "json", "--concurrency", "1")
+	assert.Equal(t, exitError, res.code)
+	assert.Contains(t, res.stderr, "5 of 6 jobs failed")
+
+	results := decodeResults(t, res.stdout)
+	require.Len(t, results, 6)
+	assert.Equal(t, &jobError{Code: "NOT_FOUND", Message: "not found", StatusCode: http.StatusNotFound}, results["missing"].Error)
+	assert.Equal(t, "validation", results["typo"].Error.Code)
+	assert.Equal(t, "validation", results["unknown"].Error.Code)
+	assert.Equal(t, "invalid_job", results["4"].Error.Code)
+	assert.Equal(t, statusOK, results["ok"].Status)
+	assert.Equal(t, "invalid_job", results["ok#6"].Error.Code)
+
+	var summary batchSummary
+	require.NoError(t, json.Unmarshal([]byte(res.stderr[:strings.LastIndex(res.stderr, "}")+1]), &summary))
+	assert.Equal(t, map[string]int{"NOT_FOUND": 1, "validation": 2, "invalid_job": 2}, summary.FailuresByCode)
+}
+
+func TestBatch_Resume(t *testing.T) {
+	server := ujeebutest.NewServer(t)
+	output := filepath.Join(t.TempDir(), "results.jsonl")
+	// A previous run completed job a and was interrupted while writing another result
+	require.NoError(t, os.WriteFile(output, []byte(`{"id":"a","status":"ok","credits":1}`+"\n"+`{"id":"b","sta`), 0o644))
+
+	res := runBatchCLI(server, batchJobs, "-o", output)
+	require.Equal(t, exitOK, res.code, res.stderr)
+	assert.Empty(t, res.stdout)
+	assert.Regexp(t, `skipped\s+1`, res.stderr)
+	server.AssertCalls(t, "card", 0)
+	server.AssertCalls(t, "extract", 1)
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 5)
+
+	// Everything is done: nothing is run again
+	res = runBatchCLI(server, batchJobs, "-o", output)
+	require.Equal(t, exitOK, res.code, res.stderr)
+	assert.Regexp(t, `skipped\s+4`, res.stderr)
+	server.AssertCalls(t, "extract", 1)
+}
+
+func TestBatch_Usage(t *testing.T) {
+	server := ujeebutest.NewServer(t)
+	res := runBatchCLI(server, "", "--concurrency", "0")
+	assert.Equal(t, exitUsage, res.code)
+
+	res = runBatchCLI(server, "", "a.jsonl", "b.jsonl")
+	assert.Equal(t, exitUsage, res.code)
+}
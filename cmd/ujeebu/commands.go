@@ -0,0 +1,211 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/ujeebu/ujeebu-go"
+)
+
+// fileResult describes a response written to a file
+type fileResult struct {
+	File        string `json:"file"`
+	Bytes       int    `json:"bytes"`
+	ContentType string `json:"content_type,omitempty"`
+	Credits     int    `json:"credits"`
+}
+
+func runExtract(e *env, args []string) error {
+	var params ujeebu.ExtractParams
+	fs, opts := e.flagSet("extract", "[url]")
+	bindParams(fs, &params)
+	rest, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+	if params.URL, err = positionalURL(params.URL, rest); err != nil {
+		return err
+	}
+
+	client, err := e.client(opts)
+	if err != nil {
+		return err
+	}
+	res, meta, err := client.ExtractWithMeta(e.ctx, params)
+	if err != nil {
+		return err
+	}
+	return e.print(opts, res, meta)
+}
+
+func runCard(e *env, args []string) error {
+	var params ujeebu.CardParams
+	fs, opts := e.flagSet("card", "[url]")
+	bindParams(fs, &params)
+	rest, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+	if params.URL, err = positionalURL(params.URL, rest); err != nil {
+		return err
+	}
+
+	client, err := e.client(opts)
+	if err != nil {
+		return err
+	}
+	res, meta, err := client.CardWithMeta(e.ctx, params)
+	if err != nil {
+		return err
+	}
+	return e.print(opts, res, meta)
+}
+
+// runScrape prints JSON responses in the selected format. Other responses are
+// written as is to the -o file, or to stdout unless they are binary.
+func runScrape(e *env, args []string) error {
+	var params ujeebu.ScrapeParams
+	fs, opts := e.flagSet("scrape", "[url]")
+	bindParams(fs, &params)
+	rest, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+	if params.URL, err = positionalURL(params.URL, rest); err != nil {
+		return err
+	}
+
+	client, err := e.client(opts)
+	if err != nil {
+		return err
+	}
+	res, meta, err := client.ScrapeRawWithMeta(e.ctx, params)
+	if err != nil {
+		return err
+	}
+
+	contentType := res.ContentType()
+	switch {
+	case strings.Contains(contentType, "json") || (contentType == "" && json.Valid(res.Body)):
+		return e.print(opts, res.Body, meta)
+	case opts.output != "":
+		return e.writeFile(opts, res, meta)
+	case isBinary(contentType):
+		return usagef("the response is %s, use -o to write it to a file", contentType)
+	default:
+		e.printMeta(opts, meta)
+		_, err = e.stdout.Write(res.Body)
+		return err
+	}
+}
+
+func runScreenshot(e *env, args []string) error {
+	var params ujeebu.ScrapeParams
+	fs, opts := e.flagSet("screenshot", "[url]")
+	bindParams(fs, &params, "ResponseType", "JSONOutput")
+	return e.scrapeToFile(fs, opts, args, &params, "screenshot", "screenshot.png")
+}
+
+func runPDF(e *env, args []string) error {
+	var params ujeebu.ScrapeParams
+	fs, opts := e.flagSet("pdf", "[url]")
+	bindParams(fs, &params, "ResponseType", "JSONOutput", "ScreenshotFullPage", "ScreenshotPartial")
+	return e.scrapeToFile(fs, opts, args, &params, "pdf", "page.pdf")
+}
+
+func runSerp(e *env, args []string) error {
+	var params ujeebu.SerpParams
+	fs, opts := e.flagSet("serp", "[query]")
+	bindParams(fs, &params)
+	rest, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		if params.Search != "" {
+			return usagef("query given both as arguments and with --search")
+		}
+		params.Search = strings.Join(rest, " ")
+	}
+
+	client, err := e.client(opts)
+	if err != nil {
+		return err
+	}
+	body, meta, err := client.SerpWithMeta(e.ctx, params)
+	if err != nil {
+		return err
+	}
+	return e.print(opts, body, meta)
+}
+
+func runAccount(e *env, args []string) error {
+	fs, opts := e.flagSet("account", "")
+	rest, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return usagef("unexpected arguments %v", rest)
+	}
+
+	client, err := e.client(opts)
+	if err != nil {
+		return err
+	}
+	res, meta, err := client.AccountWithMeta(e.ctx)
+	if err != nil {
+		return err
+	}
+	return e.print(opts, res, meta)
+}
+
+// scrapeToFile scrapes the page with the given response type and writes it to the
+// -o file, or defaultFile, then prints a description of the file
+func (e *env) scrapeToFile(fs *flag.FlagSet, opts *options, args []string, params *ujeebu.ScrapeParams, responseType, defaultFile string) error {
+	rest, err := parse(fs, opts, args)
+	if err != nil {
+		return err
+	}
+	if params.URL, err = positionalURL(params.URL, rest); err != nil {
+		return err
+	}
+	params.ResponseType = responseType
+	if opts.output == "" {
+		opts.output = defaultFile
+	}
+
+	client, err := e.client(opts)
+	if err != nil {
+		return err
+	}
+	res, meta, err := client.ScrapeRawWithMeta(e.ctx, *params)
+	if err != nil {
+		return err
+	}
+	return e.writeFile(opts, res, meta)
+}
+
+// writeFile writes the raw response to the -o file and prints a description of the file
+func (e *env) writeFile(opts *options, res *ujeebu.RawScrapeResponse, meta ujeebu.ResponseMeta) error {
+	if err := os.WriteFile(opts.output, res.Body, 0o644); err != nil {
+		return err
+	}
+	e.printMeta(opts, meta)
+	return writeOutput(e.stdout, opts.format, fileResult{
+		File:        opts.output,
+		Bytes:       len(res.Body),
+		ContentType: res.ContentType(),
+		Credits:     meta.Credits,
+	})
+}
+
+// isBinary reports whether a content type should not be printed to a terminal
+func isBinary(contentType string) bool {
+	return contentType != "" &&
+		!strings.HasPrefix(contentType, "text/") &&
+		!strings.Contains(contentType, "xml") &&
+		!strings.Contains(contentType, "javascript")
+}
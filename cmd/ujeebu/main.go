@@ -0,0 +1,274 @@
+// Command ujeebu calls the Ujeebu API from the command line.
+//
+// Usage:
+//
+//	ujeebu <command> [flags] [url | query]
+//
+// The commands are extract, scrape, screenshot, pdf, card, serp and account,
+// and batch which runs the jobs of a JSONL file.
+// Their flags map one-to-one onto the fields of ExtractParams, ScrapeParams,
+// CardParams and SerpParams, named after their JSON names (--proxy-type, --js...).
+// Run "ujeebu <command> -h" to list them.
+//
+// The API key is read from --api-key or UJEEBU_API_KEY, and the API base URL
+// from --base-url or UJEEBU_BASE_URL.
+//
+// Exit codes: 0 on success, 1 on other errors, 2 on usage errors, 3 on
+// validation errors, 4 on API errors and 5 on network errors.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/ujeebu/ujeebu-go"
+)
+
+// Exit codes
+const (
+	exitOK         = 0
+	exitError      = 1
+	exitUsage      = 2
+	exitValidation = 3
+	exitAPI        = 4
+	exitNetwork    = 5
+)
+
+// command is a ujeebu subcommand
+type command struct {
+	name    string
+	summary string
+	run     func(e *env, args []string) error
+}
+
+func commands() []command {
+	return []command{
+		{"extract", "Extract the article of a page", runExtract},
+		{"scrape", "Scrape a page", runScrape},
+		{"screenshot", "Capture a screenshot of a page to a file", runScreenshot},
+		{"pdf", "Render a page as a PDF file", runPDF},
+		{"card", "Get the preview card of a page", runCard},
+		{"serp", "Search Google", runSerp},
+		{"account", "Show the account usage and balance", runAccount},
+		{"batch", "Run the jobs of a JSONL file", runBatch},
+	}
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr, os.Getenv)
+	stop()
+	os.Exit(code)
+}
+
+// run executes the command line args and returns the exit code
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer, getenv func(string) string) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+	if args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage(stdout)
+		return exitOK
+	}
+
+	for _, cmd := range commands() {
+		if cmd.name != args[0] {
+			continue
+		}
+		err := cmd.run(&env{ctx: ctx, stdin: stdin, stdout: stdout, stderr: stderr, getenv: getenv}, args[1:])
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "ujeebu %s: %v\n", cmd.name, err)
+		}
+		return exitCode(err)
+	}
+
+	fmt.Fprintf(stderr, "ujeebu: unknown command %q\n\n", args[0])
+	usage(stderr)
+	return exitUsage
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: ujeebu <command> [flags] [url | query]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %-12s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "ujeebu <command> -h" for the flags of a command.`)
+	fmt.Fprintln(w, "The API key is read from UJEEBU_API_KEY and the base URL from UJEEBU_BASE_URL.")
+}
+
+// usageError is returned for invalid command lines
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, v ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, v...)}
+}
+
+// exitCode maps err to the exit code of the command
+func exitCode(err error) int {
+	var usageErr *usageError
+	var validationErr *ujeebu.ValidationError
+	var apiErr *ujeebu.APIError
+	var networkErr *ujeebu.NetworkError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &usageErr):
+		return exitUsage
+	case errors.As(err, &validationErr):
+		return exitValidation
+	case errors.As(err, &apiErr):
+		return exitAPI
+	case errors.As(err, &networkErr):
+		return exitNetwork
+	default:
+		return exitError
+	}
+}
+
+// env is the environment of a running command
+type env struct {
+	ctx    context.Context
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+}
+
+// options are the flags shared by every command
+type options struct {
+	apiKey  string
+	baseURL string
+	format  string
+	output  string
+	timeout time.Duration
+	verbose bool
+}
+
+// flagSet returns the flag set of a command with the shared flags registered
+func (e *env) flagSet(name, args string) (*flag.FlagSet, *options) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: ujeebu %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+
+	opts := &options{}
+	fs.StringVar(&opts.apiKey, "api-key", "", "API key (default $UJEEBU_API_KEY)")
+	fs.StringVar(&opts.baseURL, "base-url", "", "API base URL (default $UJEEBU_BASE_URL or "+ujeebu.DefaultBaseURL+")")
+	fs.StringVar(&opts.format, "format", formatJSON, "output format: json, yaml or table")
+	fs.StringVar(&opts.output, "o", "", "write the output to this file")
+	fs.DurationVar(&opts.timeout, "client-timeout", ujeebu.DefaultTimeout, "HTTP client timeout")
+	fs.BoolVar(&opts.verbose, "v", false, "print the call metadata to stderr")
+	return fs, opts
+}
+
+// parse parses args, allowing flags after positional arguments, and returns the positional arguments
+func parse(fs *flag.FlagSet, opts *options, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, &usageError{msg: err.Error()}
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if !validFormat(opts.format) {
+		return nil, usagef("unknown format %q, expected json, yaml or table", opts.format)
+	}
+	return positional, nil
+}
+
+// positionalURL returns the URL given as flag or as the single positional argument
+func positionalURL(url string, args []string) (string, error) {
+	switch {
+	case len(args) > 1:
+		return "", usagef("expected a single URL, got %d arguments", len(args))
+	case len(args) == 1 && url != "":
+		return "", usagef("URL given both as argument and with --url")
+	case len(args) == 1:
+		return args[0], nil
+	default:
+		return url, nil
+	}
+}
+
+// client creates the API client from the flags and environment
+func (e *env) client(opts *options, extra ...ujeebu.ClientOption) (*ujeebu.Client, error) {
+	apiKey := opts.apiKey
+	if apiKey == "" {
+		apiKey = e.getenv("UJEEBU_API_KEY")
+	}
+	if apiKey == "" {
+		return nil, usagef("no API key, set UJEEBU_API_KEY or use --api-key")
+	}
+
+	baseURL := opts.baseURL
+	if baseURL == "" {
+		baseURL = e.getenv("UJEEBU_BASE_URL")
+	}
+	clientOpts := []ujeebu.ClientOption{
+		ujeebu.WithTimeout(opts.timeout),
+		ujeebu.WithUserAgent(ujeebu.DefaultUserAgent + " (cli)"),
+	}
+	if baseURL != "" {
+		clientOpts = append(clientOpts, ujeebu.WithBaseURL(baseURL))
+	}
+	return ujeebu.NewClient(apiKey, append(clientOpts, extra...)...)
+}
+
+// print writes v to the output file or stdout in the selected format
+func (e *env) print(opts *options, v any, meta ujeebu.ResponseMeta) error {
+	e.printMeta(opts, meta)
+	if opts.output == "" {
+		return writeOutput(e.stdout, opts.format, v)
+	}
+
+	f, err := os.Create(opts.output)
+	if err != nil {
+		return err
+	}
+	if err := writeOutput(f, opts.format, v); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// printMeta writes the call metadata to stderr in verbose mode
+func (e *env) printMeta(opts *options, meta ujeebu.ResponseMeta) {
+	if !opts.verbose {
+		return
+	}
+	fmt.Fprintf(e.stderr, "endpoint=%s status=%d credits=%d attempts=%d duration=%s",
+		meta.Endpoint, meta.StatusCode, meta.Credits, meta.Attempts, meta.Duration.Round(time.Millisecond))
+	if meta.RequestID != "" {
+		fmt.Fprintf(e.stderr, " request_id=%s", meta.RequestID)
+	}
+	fmt.Fprintln(e.stderr)
+}
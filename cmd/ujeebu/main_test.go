@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ujeebu/ujeebu-go"
+	"github.com/ujeebu/ujeebu-go/ujeebutest"
+	"gopkg.in/yaml.v3"
+)
+
+type result struct {
+	code   int
+	stdout string
+	stderr string
+}
+
+// runCLI runs the command line against server with the API key and base URL set in the environment
+func runCLI(server *ujeebutest.Server, args ...string) result {
+	var stdout, stderr bytes.Buffer
+	vars := map[string]string{"UJEEBU_API_KEY": ujeebutest.TestAPIKey}
+	if server != nil {
+		vars["UJEEBU_BASE_URL"] = server.URL
+	}
+	code := run(context.Background(), args, nil, &stdout, &stderr, func(name string) string { return vars[name] })
+	return result{code: code, stdout: stdout.String(), stderr: stderr.String()}
+}
+
+func TestRun_Usage(t *testing.T) {
+	res := runCLI(nil)
+	assert.Equal(t, exitUsage, res.code)
+	assert.Contains(t, res.stderr, "screenshot")
+
+	res = runCLI(nil, "help")
+	assert.Equal(t, exitOK, res.code)
+
+	res = runCLI(nil, "unknown")
+	assert.Equal(t, exitUsage, res.code)
+	assert.Contains(t, res.stderr, `unknown command "unknown"`)
+
+	res = runCLI(nil, "extract", "--no-such-flag")
+	assert.Equal(t, exitUsage, res.code)
+
+	res = runCLI(nil, "extract", "-h")
+	assert.Equal(t, exitOK, res.code)
+	assert.Contains(t, res.stderr, "-proxy-type")
+
+	res = runCLI(nil, "card", "--format", "xml", "https://example.com")
+	assert.Equal(t, exitUsage, res.code)
+}
+
+func TestRun_MissingAPIKey(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), []string{"account"}, nil, &stdout, &stderr, func(string) string { return "" })
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr.String(), "UJEEBU_API_KEY")
+}
+
+func TestRun_ExtractFlags(t *testing.T) {
+	server := ujeebutest.NewServer(t)
+
+	res := runCLI(server, "extract", "https://example.com/post", "--js", "--proxy-type", "residential",
+		"--timeout", "30", "--header", "Authorization=Bearer x", "--fast-mode")
+	require.Equal(t, exitOK, res.code, res.stderr)
+
+	var article ujeebu.ExtractResponse
+	require.NoError(t, json.Unmarshal([]byte(res.stdout), &article))
+	assert.Equal(t, "https://example.com/post", article.Article.URL)
+
+	req, ok := server.LastRequest("extract")
+	require.True(t, ok)
+	assert.Equal(t, "true", req.Param("js"))
+	assert.Equal(t, "residential", req.Param("proxy_type"))
+	assert.Equal(t, "30", req.Param("timeout"))
+	assert.Equal(t, "d15de7", req.Param("mode"))
+	assert.Equal(t, "Bearer x", req.Header.Get("UJB-Authorization"))
+}
+
+func TestRun_Formats(t *testing.T) {
+	server := ujeebutest.NewServer(t)
+
+	res := runCLI(server, "card", "--format", "yaml", "https://example.com")
+	require.Equal(t, exitOK, res.code, res.stderr)
+	var card ujeebu.CardResponse
+	require.NoError(t, yaml.Unmarshal([]byte(res.stdout), &card))
+	assert.Equal(t, "https://example.com", card.URL)
+
+	res = runCLI(server, "account", "--format", "table", "-v")
+	require.Equal(t, exitOK, res.code, res.stderr)
+	assert.Contains(t, res.stdout, "FIELD")
+	assert.Regexp(t, `balance\s+\d+`, res.stdout)
+	assert.Contains(t, res.stderr, "endpoint=account")
+
+	res = runCLI(server, "serp", "golang", "tutorial", "--format", "table")
+	require.Equal(t, exitOK, res.code, res.stderr)
+	assert.Contains(t, res.stdout, "organic_results.0.link")
+	server.AssertParam(t, "serp", "search", "golang tutorial")
+}
+
+func TestRun_BinaryOutputs(t *testing.T) {
+	server := ujeebutest.NewServer(t)
+	dir := t.TempDir()
+	file := filepath.Join(dir, "shot.png")
+
+	res := runCLI(server, "screenshot", "https://example.com", "--screenshot-fullpage", "-o", file)
+	require.Equal(t, exitOK, res.code, res.stderr)
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, ujeebutest.PNG, data)
+
+	var written fileResult
+	require.NoError(t, json.Unmarshal([]byte(res.stdout), &written))
+	assert.Equal(t, file, written.File)
+	assert.Equal(t, len(ujeebutest.PNG), written.Bytes)
+	server.AssertParam(t, "scrape", "screenshot_fullpage", "true")
+
+	pdf := filepath.Join(dir, "page.pdf")
+	res = runCLI(server, "pdf", "https://example.com", "-o", pdf)
+	require.Equal(t, exitOK, res.code, res.stderr)
+	data, err = os.ReadFile(pdf)
+	require.NoError(t, err)
+	assert.Equal(t, ujeebutest.PDF, data)
+
+	// Binary scrape responses are not printed
+	res = runCLI(server, "scrape", "https://example.com", "--response-type", "pdf")
+	assert.Equal(t, exitUsage, res.code)
+	assert.Contains(t, res.stderr, "use -o")
+
+	res = runCLI(server, "scrape", "https://example.com")
+	require.Equal(t, exitOK, res.code, res.stderr)
+	assert.Contains(t, res.stdout, "<html")
+}
+
+func TestRun_ExitCodes(t *testing.T) {
+	server := ujeebutest.NewServer(t)
+
+	res := runCLI(server, "card")
+	assert.Equal(t, exitValidation, res.code)
+
+	server.Fail("card", ujeebutest.Fault{StatusCode: http.StatusNotFound, Message: "not found", Times: 1})
+	res = runCLI(server, "card", "https://example.com")
+	assert.Equal(t, exitAPI, res.code)
+	assert.Contains(t, res.stderr, "not found")
+
+	res = runCLI(nil, "card", "--base-url", "http://127.0.0.1:1", "https://example.com")
+	assert.Equal(t, exitNetwork, res.code)
+}
+
+func TestSnakeCase(t *testing.T) {
+	assert.Equal(t, "fast_mode", snakeCase("FastMode"))
+	assert.Equal(t, "custom_js", snakeCase("CustomJS"))
+	assert.Equal(t, "url", snakeCase("URL"))
+}
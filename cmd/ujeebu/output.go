@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats
+const (
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+	formatTable = "table"
+)
+
+// maxCellWidth is the width beyond which table values are truncated
+const maxCellWidth = 100
+
+// validFormat reports whether format is a supported output format
+func validFormat(format string) bool {
+	return format == formatJSON || format == formatYAML || format == formatTable
+}
+
+// writeOutput writes v to w in the given format
+func writeOutput(w io.Writer, format string, v any) error {
+	generic, err := toGeneric(v)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case formatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(generic); err != nil {
+			return err
+		}
+		return enc.Close()
+	case formatTable:
+		return writeTable(w, generic)
+	default:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(generic)
+	}
+}
+
+// toGeneric converts v to maps, slices and scalars following its JSON encoding
+func toGeneric(v any) (any, error) {
+	var data []byte
+	switch b := v.(type) {
+	case []byte:
+		data = b
+	case json.RawMessage:
+		data = b
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	return generic, nil
+}
+
+// writeTable writes v as a two-column table of dotted paths and values
+func writeTable(w io.Writer, v any) error {
+	var rows [][2]string
+	flatten("", v, &rows)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tVALUE")
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%s\n", row[0], row[1])
+	}
+	return tw.Flush()
+}
+
+// flatten appends the scalar leaves of v to rows, keyed by their dotted path
+func flatten(path string, v any, rows *[][2]string) {
+	switch t := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			flatten(joinPath(path, k), t[k], rows)
+		}
+	case []any:
+		for i, item := range t {
+			flatten(joinPath(path, strconv.Itoa(i)), item, rows)
+		}
+	case nil:
+		// Null values are omitted
+	default:
+		*rows = append(*rows, [2]string{path, cell(t)})
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// cell formats a scalar on a single line
+func cell(v any) string {
+	var s string
+	switch t := v.(type) {
+	case string:
+		s = t
+	case float64:
+		s = strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		s = fmt.Sprint(t)
+	}
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > maxCellWidth {
+		s = string(r[:maxCellWidth-3]) + "..."
+	}
+	return s
+}
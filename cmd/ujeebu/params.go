@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// bindParams registers one flag per field of the params struct pointed to by params.
+// Flag names are the JSON names of the fields with dashes, e.g. --proxy-type for ProxyType.
+// Fields listed in skip are not registered.
+func bindParams(fs *flag.FlagSet, params any, skip ...string) {
+	v := reflect.ValueOf(params).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || contains(skip, field.Name) {
+			continue
+		}
+		name := flagName(field)
+		usage := fmt.Sprintf("%s.%s", t.Name(), field.Name)
+		ptr := v.Field(i).Addr().Interface()
+
+		switch p := ptr.(type) {
+		case *string:
+			fs.StringVar(p, name, "", usage)
+		case *bool:
+			fs.BoolVar(p, name, false, usage)
+		case *int:
+			fs.IntVar(p, name, 0, usage)
+		case *map[string]string:
+			fs.Var(headerFlag{p}, name, usage+" (Name=Value, repeatable)")
+		case *map[string]any:
+			fs.Var(jsonFlag{p}, name, usage+" (JSON object)")
+		default:
+			panic(fmt.Sprintf("ujeebu: unsupported flag type %s for %s", field.Type, usage))
+		}
+	}
+}
+
+// flagName returns the flag name of a params field
+func flagName(field reflect.StructField) string {
+	if field.Type == reflect.TypeOf(map[string]string(nil)) {
+		return "header"
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		name = snakeCase(field.Name)
+	}
+	return strings.ReplaceAll(name, "_", "-")
+}
+
+// snakeCase converts a Go field name to snake case (FastMode -> fast_mode)
+func snakeCase(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// headerFlag collects repeated Name=Value flags into a map
+type headerFlag struct {
+	m *map[string]string
+}
+
+func (f headerFlag) String() string {
+	if f.m == nil || *f.m == nil {
+		return ""
+	}
+	pairs := make([]string, 0, len(*f.m))
+	for k, v := range *f.m {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (f headerFlag) Set(value string) error {
+	name, val, ok := strings.Cut(value, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("expected Name=Value, got %q", value)
+	}
+	if *f.m == nil {
+		*f.m = make(map[string]string)
+	}
+	(*f.m)[name] = val
+	return nil
+}
+
+// jsonFlag decodes a JSON object flag
+type jsonFlag struct {
+	m *map[string]any
+}
+
+func (f jsonFlag) String() string {
+	if f.m == nil || *f.m == nil {
+		return ""
+	}
+	b, _ := json.Marshal(*f.m)
+	return string(b)
+}
+
+func (f jsonFlag) Set(value string) error {
+	return json.Unmarshal([]byte(value), f.m)
+}
@@ -0,0 +1,412 @@
+// Package crawl crawls sites through the Ujeebu Scrape API.
+//
+// A Crawler fetches the seed URLs, extracts the links of every HTML page and
+// follows those accepted by its options, emitting each page to a handler:
+//
+//	crawler, err := crawl.New(client, crawl.Options{
+//		Params:   ujeebu.ScrapeParams{JS: true, ProxyType: "residential"},
+//		MaxDepth: 2,
+//		MaxPages: 100,
+//		Include:  []string{`^https://example\.com/blog/`},
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	stats, err := crawler.Run(ctx, []string{"https://example.com/blog/"}, func(ctx context.Context, page *crawl.Page) error {
+//		if page.Err != nil {
+//			log.Printf("%s: %v", page.URL, page.Err)
+//			return nil
+//		}
+//		fmt.Println(page.URL, len(page.Body))
+//		return nil
+//	})
+package crawl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ujeebu/ujeebu-go"
+	"github.com/ujeebu/ujeebu-go/robots"
+)
+
+// DefaultConcurrency is the number of pages fetched in parallel when Options.Concurrency is not set
+const DefaultConcurrency = ujeebu.DefaultBatchConcurrency
+
+// ErrSkipLinks is returned by a Handler to not follow the links of the page.
+// It is not returned as an error by Run.
+var ErrSkipLinks = errors.New("crawl: skip the links of this page")
+
+// ErrStop is returned by a Handler to stop the crawl once the pages being fetched are handled.
+// It is not returned as an error by Run.
+var ErrStop = errors.New("crawl: stop the crawl")
+
+// Options configures a Crawler
+type Options struct {
+	// Params are the scrape parameters of every page (JS, proxy, session, headers...).
+	// URL is set to the page URL and the page is always fetched as raw HTML, so
+	// ResponseType, JSONOutput and ExtractRules are ignored.
+	Params ujeebu.ScrapeParams
+	// MaxDepth is the maximum number of links followed from a seed (unlimited if zero)
+	MaxDepth int
+	// MaxPages is the maximum number of pages fetched, including failures (unlimited if zero)
+	MaxPages int
+	// Concurrency is the maximum number of pages fetched in parallel (DefaultConcurrency if zero)
+	Concurrency int
+	// AllowedHosts are the hosts links are followed to, the hosts of the seeds if empty.
+	// A host starting with a dot, such as ".example.com", also allows its subdomains.
+	AllowedHosts []string
+	// AnyHost follows links to any host, ignoring AllowedHosts
+	AnyHost bool
+	// Include are regular expressions the canonical URL of a link must match one of to be followed
+	Include []string
+	// Exclude are regular expressions of canonical link URLs that are not followed
+	Exclude []string
+	// IgnoreQuery removes the query string of links, for sites where it does not change the content
+	IgnoreQuery bool
+	// FollowNofollow follows links with rel="nofollow", which are skipped by default
+	FollowNofollow bool
+	// Robots, if set, skips the URLs disallowed by the robots.txt of their host and spaces the
+	// requests to each host by its Crawl-delay. Hosts whose robots.txt is unreachable are disallowed.
+	Robots *robots.Cache
+	// Delay is the minimum delay between requests to the same host, raised by Crawl-delay
+	Delay time.Duration
+	// MaxDelay caps the Crawl-delay of robots.txt files (no limit if zero)
+	MaxDelay time.Duration
+}
+
+// Page is a fetched page
+type Page struct {
+	// URL is the canonical URL of the page
+	URL string
+	// Depth is the number of links followed from a seed to the page
+	Depth int
+	// Referrer is the URL of the page the link was found in, empty for seeds
+	Referrer string
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Headers are the response headers
+	Headers http.Header
+	// Body is the page content
+	Body []byte
+	// Links are the links of the page, including the ones that are not followed
+	Links []Link
+	// Meta is the metadata of the scrape call
+	Meta ujeebu.ResponseMeta
+	// Err is the error of the scrape call, the other fields but URL, Depth, Referrer and Meta are empty
+	Err error
+}
+
+// ContentType returns the Content-Type header of the page
+func (p *Page) ContentType() string {
+	return p.Headers.Get("Content-Type")
+}
+
+// Handler processes a page. It is called concurrently for up to Options.Concurrency pages,
+// including for pages that failed to be fetched. Returning ErrSkipLinks does not follow the
+// links of the page, ErrStop ends the crawl, and any other error aborts the crawl and is
+// returned by Run.
+type Handler func(ctx context.Context, page *Page) error
+
+// Stats summarizes a crawl
+type Stats struct {
+	// Pages is the number of pages fetched, including failures
+	Pages int
+	// Failed is the number of pages that failed to be fetched
+	Failed int
+	// Credits is the number of credits spent
+	Credits int
+	// Queued is the number of links left to fetch when the crawl ended
+	Queued int
+	// Disallowed is the number of URLs skipped because of robots.txt
+	Disallowed int
+}
+
+// Crawler crawls sites through the Scrape API. A Crawler can run several crawls, sequentially or concurrently.
+type Crawler struct {
+	scraper ujeebu.Scraper
+	opts    Options
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// New creates a Crawler fetching pages with scraper, usually a *ujeebu.Client
+func New(scraper ujeebu.Scraper, opts Options) (*Crawler, error) {
+	if scraper == nil {
+		return nil, &ujeebu.ValidationError{Field: "scraper", Message: "scraper is required"}
+	}
+	if opts.MaxDepth < 0 || opts.MaxPages < 0 || opts.Concurrency < 0 || opts.Delay < 0 || opts.MaxDelay < 0 {
+		return nil, &ujeebu.ValidationError{Field: "Options", Message: "MaxDepth, MaxPages, Concurrency, Delay and MaxDelay must not be negative"}
+	}
+	if opts.Concurrency == 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+
+	c := &Crawler{scraper: scraper, opts: opts}
+	var err error
+	if c.include, err = compilePatterns("Include", opts.Include); err != nil {
+		return nil, err
+	}
+	if c.exclude, err = compilePatterns("Exclude", opts.Exclude); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func compilePatterns(field string, patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, &ujeebu.ValidationError{Field: field, Message: fmt.Sprintf("invalid pattern %q: %v", pattern, err)}
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// task is a page to fetch
+type task struct {
+	url      string
+	depth    int
+	referrer string
+}
+
+// outcome is the result of a visited page
+type outcome struct {
+	page   *Page
+	follow bool
+	err    error
+	// disallowed is set when robots.txt disallows the page, which is not fetched
+	disallowed bool
+}
+
+// Run crawls from the seed URLs, breadth first, and calls handler for every fetched page.
+// It returns when no link is left to follow, a page limit is reached, the handler returns
+// ErrStop or an error, or ctx is done, after the pages being fetched are handled.
+func (c *Crawler) Run(ctx context.Context, seeds []string, handler Handler) (Stats, error) {
+	var stats Stats
+	if handler == nil {
+		return stats, &ujeebu.ValidationError{Field: "handler", Message: "handler is required"}
+	}
+	if len(seeds) == 0 {
+		return stats, &ujeebu.ValidationError{Field: "seeds", Message: "at least one seed URL is required"}
+	}
+
+	hosts := c.opts.AllowedHosts
+	seen := map[string]bool{}
+	var queue []task
+	for _, seed := range seeds {
+		canonical, err := c.canonicalize(seed)
+		if err != nil {
+			return stats, &ujeebu.ValidationError{Field: "seeds", Message: fmt.Sprintf("invalid seed URL %q: %v", seed, err)}
+		}
+		if len(c.opts.AllowedHosts) == 0 {
+			u, _ := url.Parse(canonical)
+			hosts = append(hosts, u.Hostname())
+		}
+		if !seen[canonical] {
+			seen[canonical] = true
+			queue = append(queue, task{url: canonical})
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	scheduler := robots.NewScheduler(c.opts.Delay, c.opts.MaxDelay)
+	results := make(chan outcome)
+	var wg sync.WaitGroup
+	inFlight := 0
+	stopped := false
+	var runErr error
+	for {
+		for len(queue) > 0 && inFlight < c.opts.Concurrency && !stopped && ctx.Err() == nil &&
+			(c.opts.MaxPages == 0 || stats.Pages < c.opts.MaxPages) {
+			t := queue[0]
+			queue = queue[1:]
+			inFlight++
+			stats.Pages++
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results <- c.visit(ctx, t, handler, scheduler)
+			}()
+		}
+		if inFlight == 0 {
+			break
+		}
+
+		res := <-results
+		inFlight--
+		if res.disallowed {
+			stats.Pages--
+			stats.Disallowed++
+			continue
+		}
+		stats.Credits += res.page.Meta.Credits
+		if res.page.Err != nil {
+			stats.Failed++
+		}
+		switch {
+		case errors.Is(res.err, ErrStop):
+			stopped = true
+		case res.err != nil:
+			if runErr == nil {
+				runErr = res.err
+			}
+			stopped = true
+			cancel()
+		}
+		if !res.follow || stopped || (c.opts.MaxDepth > 0 && res.page.Depth >= c.opts.MaxDepth) {
+			continue
+		}
+		for _, link := range res.page.Links {
+			target, ok := c.follow(link, hosts)
+			if !ok || seen[target] {
+				continue
+			}
+			seen[target] = true
+			queue = append(queue, task{url: target, depth: res.page.Depth + 1, referrer: res.page.URL})
+		}
+	}
+	wg.Wait()
+
+	stats.Queued = len(queue)
+	if runErr == nil && !stopped {
+		runErr = ctx.Err()
+	}
+	return stats, runErr
+}
+
+// visit fetches the page of t once allowed by robots.txt and scheduler, and calls handler
+func (c *Crawler) visit(ctx context.Context, t task, handler Handler, scheduler *robots.Scheduler) outcome {
+	page := &Page{URL: t.url, Depth: t.depth, Referrer: t.referrer}
+	if err := c.wait(ctx, t.url, scheduler); err != nil {
+		if errors.Is(err, errDisallowed) {
+			return outcome{page: page, disallowed: true}
+		}
+		page.Err = err
+		if err := handler(ctx, page); !errors.Is(err, ErrSkipLinks) {
+			return outcome{page: page, err: err}
+		}
+		return outcome{page: page}
+	}
+
+	params := c.opts.Params
+	params.URL = t.url
+	params.ResponseType = "html"
+	params.JSONOutput = false
+	params.ExtractRules = nil
+	res, meta, err := c.scraper.ScrapeRawWithMeta(ctx, params)
+	page.Meta = meta
+	if err != nil {
+		page.Err = err
+	} else {
+		page.StatusCode = res.StatusCode
+		page.Headers = res.Headers
+		page.Body = res.Body
+		if isHTML(res.ContentType()) {
+			page.Links, _ = ExtractLinks(t.url, res.Body)
+		}
+	}
+
+	err = handler(ctx, page)
+	if errors.Is(err, ErrSkipLinks) {
+		return outcome{page: page}
+	}
+	return outcome{page: page, follow: page.Err == nil, err: err}
+}
+
+// errDisallowed is returned by wait for URLs disallowed by robots.txt
+var errDisallowed = errors.New("crawl: disallowed by robots.txt")
+
+// wait checks that robots.txt allows pageURL and waits for the delay of its host
+func (c *Crawler) wait(ctx context.Context, pageURL string, scheduler *robots.Scheduler) error {
+	var delay time.Duration
+	if c.opts.Robots != nil {
+		r, err := c.opts.Robots.Get(ctx, pageURL)
+		if err != nil {
+			return err
+		}
+		if !r.Allowed(c.opts.Robots.UserAgent(), pageURL) {
+			return errDisallowed
+		}
+		delay = r.CrawlDelay(c.opts.Robots.UserAgent())
+	}
+	u, _ := url.Parse(pageURL)
+	return scheduler.Wait(ctx, u.Host, delay)
+}
+
+// follow returns the URL to fetch for link, and false if it is not followed
+func (c *Crawler) follow(link Link, hosts []string) (string, bool) {
+	if link.Nofollow && !c.opts.FollowNofollow {
+		return "", false
+	}
+	target, err := c.canonicalize(link.URL)
+	if err != nil {
+		return "", false
+	}
+	if !c.opts.AnyHost {
+		u, _ := url.Parse(target)
+		if !hostAllowed(u.Hostname(), hosts) {
+			return "", false
+		}
+	}
+	if len(c.include) > 0 && !matchAny(c.include, target) {
+		return "", false
+	}
+	if matchAny(c.exclude, target) {
+		return "", false
+	}
+	return target, true
+}
+
+// canonicalize returns the canonical URL of a page, without query string with IgnoreQuery
+func (c *Crawler) canonicalize(rawURL string) (string, error) {
+	canonical, err := Canonicalize(rawURL)
+	if err != nil || !c.opts.IgnoreQuery {
+		return canonical, err
+	}
+	if i := strings.IndexByte(canonical, '?'); i >= 0 {
+		canonical = canonical[:i]
+	}
+	return canonical, nil
+}
+
+// hostAllowed reports whether host matches one of hosts, entries starting with a dot matching subdomains
+func hostAllowed(host string, hosts []string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range hosts {
+		allowed = strings.ToLower(allowed)
+		if strings.HasPrefix(allowed, ".") {
+			if host == allowed[1:] || strings.HasSuffix(host, allowed) {
+				return true
+			}
+		} else if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAny(patterns []*regexp.Regexp, s string) bool {
+	for _, re := range patterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// isHTML reports whether links are extracted from a page of this content type
+func isHTML(contentType string) bool {
+	return contentType == "" || strings.Contains(contentType, "html")
+}
@@ -0,0 +1,365 @@
+package crawl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ujeebu/ujeebu-go"
+	"github.com/ujeebu/ujeebu-go/robots"
+	"github.com/ujeebu/ujeebu-go/ujeebutest"
+)
+
+// site is a fake site served by the scrape endpoint, by page URL
+var site = map[string]string{
+	"https://example.com/":              `<a href="/blog/">Blog</a> <a href="/about">About</a> <a href="https://other.com/">Other</a>`,
+	"https://example.com/about":         `<a href="/">Home</a> <a href="/team?utm_source=nav">Team</a>`,
+	"https://example.com/team":          `<a href="/about">About</a> <a href="/private/" rel="nofollow">Private</a>`,
+	"https://example.com/blog/":         `<a href="/blog/1">One</a> <a href="/blog/2">Two</a> <a href="/blog/feed.xml">Feed</a>`,
+	"https://example.com/blog/1":        `<a href="/blog/2#top">Two</a> <a href="https://docs.example.com/">Docs</a>`,
+	"https://example.com/blog/2":        `<a href="/blog/3">Three</a>`,
+	"https://example.com/blog/3":        `<p>The end</p>`,
+	"https://example.com/private/":      `<p>Private</p>`,
+	"https://example.com/blog/feed.xml": `<rss><a href="/hidden">Hidden</a></rss>`,
+	"https://docs.example.com/":         `<a href="https://example.com/">Home</a>`,
+	"https://other.com/":                `<a href="/elsewhere">Elsewhere</a>`,
+}
+
+// siteRobots is the robots.txt of example.com
+const siteRobots = "User-agent: *\nDisallow: /blog/2\nDisallow: /team\nCrawl-delay: 0.02\n"
+
+func newSiteServer(t *testing.T) *ujeebutest.Server {
+	server := ujeebutest.NewServer(t)
+	server.Handle("scrape", func(w http.ResponseWriter, r *http.Request) {
+		u := r.URL.Query().Get("url")
+		if u == "https://example.com/robots.txt" {
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(siteRobots))
+			return
+		}
+		body, ok := site[u]
+		if !ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "page not found", "error_code": "NOT_FOUND"}`))
+			return
+		}
+		contentType := "text/html; charset=utf-8"
+		if strings.HasSuffix(u, ".xml") {
+			contentType = "application/rss+xml"
+		}
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write([]byte("<html><body>" + body + "</body></html>"))
+	})
+	return server
+}
+
+// collector records the handled pages
+type collector struct {
+	mu    sync.Mutex
+	pages map[string]*Page
+}
+
+func (c *collector) handle(ctx context.Context, page *Page) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pages == nil {
+		c.pages = map[string]*Page{}
+	}
+	c.pages[page.URL] = page
+	return nil
+}
+
+func (c *collector) urls() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var urls []string
+	for u := range c.pages {
+		urls = append(urls, u)
+	}
+	sort.Strings(urls)
+	return urls
+}
+
+func TestCrawler_Run(t *testing.T) {
+	server := newSiteServer(t)
+	crawler, err := New(server.Client(), Options{
+		Params:      ujeebu.ScrapeParams{JS: true, ProxyType: "residential", SessionID: "s1", ResponseType: "screenshot"},
+		Concurrency: 3,
+	})
+	require.NoError(t, err)
+
+	var pages collector
+	stats, err := crawler.Run(context.Background(), []string{"https://EXAMPLE.com"}, pages.handle)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"https://example.com/",
+		"https://example.com/about",
+		"https://example.com/blog/",
+		"https://example.com/blog/1",
+		"https://example.com/blog/2",
+		"https://example.com/blog/3",
+		"https://example.com/blog/feed.xml",
+		"https://example.com/team",
+	}, pages.urls())
+	assert.Equal(t, Stats{Pages: 8, Credits: 8 * ujeebutest.DefaultCredits["scrape"]}, stats)
+
+	post := pages.pages["https://example.com/blog/2"]
+	assert.Equal(t, 2, post.Depth)
+	assert.Contains(t, []string{"https://example.com/blog/", "https://example.com/blog/1"}, post.Referrer)
+	assert.Equal(t, http.StatusOK, post.StatusCode)
+	assert.Contains(t, string(post.Body), "Three")
+	assert.Equal(t, []Link{{URL: "https://example.com/blog/3"}}, post.Links)
+	assert.Empty(t, pages.pages["https://example.com/blog/feed.xml"].Links)
+	assert.Equal(t, []Link{
+		{URL: "https://example.com/about"},
+		{URL: "https://example.com/private/", Nofollow: true},
+	}, pages.pages["https://example.com/team"].Links)
+
+	// Scrape settings are inherited, but pages are always fetched as raw HTML
+	req, ok := server.LastRequest("scrape")
+	require.True(t, ok)
+	assert.Equal(t, "true", req.Param("js"))
+	assert.Equal(t, "residential", req.Param("proxy_type"))
+	assert.Equal(t, "s1", req.Param("session_id"))
+	assert.Equal(t, "html", req.Param("response_type"))
+	assert.Empty(t, req.Param("json"))
+}
+
+func TestCrawler_Limits(t *testing.T) {
+	server := newSiteServer(t)
+	crawler, err := New(server.Client(), Options{MaxDepth: 1, Concurrency: 1})
+	require.NoError(t, err)
+
+	var pages collector
+	stats, err := crawler.Run(context.Background(), []string{"https://example.com/"}, pages.handle)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/", "https://example.com/about", "https://example.com/blog/"}, pages.urls())
+	assert.Equal(t, 3, stats.Pages)
+
+	crawler, err = New(server.Client(), Options{MaxPages: 2, Concurrency: 1})
+	require.NoError(t, err)
+	pages = collector{}
+	stats, err = crawler.Run(context.Background(), []string{"https://example.com/"}, pages.handle)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/", "https://example.com/blog/"}, pages.urls())
+	assert.Equal(t, 2, stats.Pages)
+	assert.Equal(t, 4, stats.Queued)
+}
+
+func TestCrawler_Filters(t *testing.T) {
+	server := newSiteServer(t)
+	crawler, err := New(server.Client(), Options{
+		AllowedHosts:   []string{".example.com"},
+		Include:        []string{`/blog/`, `^https://docs\.`},
+		Exclude:        []string{`\.xml$`, `/blog/3$`},
+		FollowNofollow: true,
+	})
+	require.NoError(t, err)
+
+	var pages collector
+	_, err = crawler.Run(context.Background(), []string{"https://example.com/"}, pages.handle)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"https://docs.example.com/",
+		"https://example.com/",
+		"https://example.com/blog/",
+		"https://example.com/blog/1",
+		"https://example.com/blog/2",
+	}, pages.urls())
+
+	crawler, err = New(server.Client(), Options{AnyHost: true, MaxDepth: 1})
+	require.NoError(t, err)
+	pages = collector{}
+	_, err = crawler.Run(context.Background(), []string{"https://example.com/"}, pages.handle)
+	require.NoError(t, err)
+	assert.Contains(t, pages.urls(), "https://other.com/")
+}
+
+func TestCrawler_Robots(t *testing.T) {
+	server := newSiteServer(t)
+	cache, err := robots.NewCache(server.Client(), robots.Options{})
+	require.NoError(t, err)
+	crawler, err := New(server.Client(), Options{Robots: cache, MaxPages: 6, AllowedHosts: []string{".example.com"}})
+	require.NoError(t, err)
+
+	var pages collector
+	start := time.Now()
+	stats, err := crawler.Run(context.Background(), []string{"https://example.com/"}, pages.handle)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"https://docs.example.com/",
+		"https://example.com/",
+		"https://example.com/about",
+		"https://example.com/blog/",
+		"https://example.com/blog/1",
+		"https://example.com/blog/feed.xml",
+	}, pages.urls())
+	assert.Equal(t, 6, stats.Pages)
+	assert.Equal(t, 2, stats.Disallowed)
+
+	// Requests to example.com are spaced by its Crawl-delay
+	assert.GreaterOrEqual(t, time.Since(start), 4*20*time.Millisecond)
+
+	// Hosts whose robots.txt is unreachable are disallowed
+	server.Fail("scrape", ujeebutest.Fault{StatusCode: http.StatusInternalServerError, Message: "unreachable", Times: 1})
+	cache, err = robots.NewCache(server.Client(), robots.Options{})
+	require.NoError(t, err)
+	crawler, err = New(server.Client(), Options{Robots: cache, MaxDepth: 1})
+	require.NoError(t, err)
+	pages = collector{}
+	stats, err = crawler.Run(context.Background(), []string{"https://example.com/blog/3"}, pages.handle)
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Disallowed: 1}, stats)
+	assert.Empty(t, pages.urls())
+
+	// ErrSkipLinks is not fatal for pages whose robots.txt lookup failed
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mock := &ujeebutest.MockAPI{}
+	mock.ScrapeFunc = func(ctx context.Context, params ujeebu.ScrapeParams) (*ujeebu.RawScrapeResponse, ujeebu.ResponseMeta, error) {
+		cancel()
+		return nil, ujeebu.ResponseMeta{}, &ujeebu.NetworkError{Err: ctx.Err()}
+	}
+	cache, err = robots.NewCache(mock, robots.Options{})
+	require.NoError(t, err)
+	crawler, err = New(mock, Options{Robots: cache})
+	require.NoError(t, err)
+	var failed []*Page
+	stats, err = crawler.Run(ctx, []string{"https://example.com/"}, func(ctx context.Context, page *Page) error {
+		failed = append(failed, page)
+		return ErrSkipLinks
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	require.Len(t, failed, 1)
+	assert.ErrorIs(t, failed[0].Err, context.Canceled)
+	assert.Equal(t, 1, stats.Failed)
+}
+
+func TestCrawler_Failures(t *testing.T) {
+	server := newSiteServer(t)
+	crawler, err := New(server.Client(), Options{})
+	require.NoError(t, err)
+
+	var pages collector
+	stats, err := crawler.Run(context.Background(), []string{"https://example.com/missing", "https://example.com/blog/3"}, pages.handle)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Pages)
+	assert.Equal(t, 1, stats.Failed)
+
+	missing := pages.pages["https://example.com/missing"]
+	var apiErr *ujeebu.APIError
+	require.ErrorAs(t, missing.Err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Nil(t, missing.Body)
+}
+
+func TestCrawler_HandlerControl(t *testing.T) {
+	server := newSiteServer(t)
+	crawler, err := New(server.Client(), Options{Concurrency: 1})
+	require.NoError(t, err)
+
+	// ErrSkipLinks prunes the blog
+	var visited []string
+	_, err = crawler.Run(context.Background(), []string{"https://example.com/"}, func(ctx context.Context, page *Page) error {
+		visited = append(visited, page.URL)
+		if strings.HasPrefix(page.URL, "https://example.com/blog/") {
+			return ErrSkipLinks
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/", "https://example.com/blog/", "https://example.com/about", "https://example.com/team"}, visited)
+
+	// ErrStop ends the crawl without error
+	visited = nil
+	stats, err := crawler.Run(context.Background(), []string{"https://example.com/"}, func(ctx context.Context, page *Page) error {
+		visited = append(visited, page.URL)
+		return ErrStop
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/"}, visited)
+	assert.Equal(t, 1, stats.Pages)
+
+	// Other errors abort the crawl
+	boom := errors.New("boom")
+	_, err = crawler.Run(context.Background(), []string{"https://example.com/"}, func(ctx context.Context, page *Page) error {
+		return boom
+	})
+	assert.ErrorIs(t, err, boom)
+}
+
+func TestCrawler_Cancel(t *testing.T) {
+	server := newSiteServer(t)
+	crawler, err := New(server.Client(), Options{Concurrency: 1})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	count := 0
+	stats, err := crawler.Run(ctx, []string{"https://example.com/"}, func(ctx context.Context, page *Page) error {
+		count++
+		cancel()
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, 1, stats.Pages)
+}
+
+func TestCrawler_IgnoreQuery(t *testing.T) {
+	var mu sync.Mutex
+	var fetched []string
+	mock := &ujeebutest.MockAPI{}
+	mock.ScrapeFunc = func(ctx context.Context, params ujeebu.ScrapeParams) (*ujeebu.RawScrapeResponse, ujeebu.ResponseMeta, error) {
+		mu.Lock()
+		fetched = append(fetched, params.URL)
+		mu.Unlock()
+		u, _ := url.Parse(params.URL)
+		body := ""
+		if u.Path == "/" {
+			body = `<a href="/list?page=1">1</a><a href="/list?page=2">2</a>`
+		}
+		return &ujeebu.RawScrapeResponse{Body: []byte(body), StatusCode: http.StatusOK, Headers: http.Header{}}, ujeebu.ResponseMeta{Credits: 1}, nil
+	}
+
+	crawler, err := New(mock, Options{IgnoreQuery: true})
+	require.NoError(t, err)
+	stats, err := crawler.Run(context.Background(), []string{"https://example.com/?ref=home"}, func(ctx context.Context, page *Page) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/", "https://example.com/list"}, fetched)
+	assert.Equal(t, 2, stats.Credits)
+}
+
+func TestNew_Validation(t *testing.T) {
+	server := newSiteServer(t)
+	var validationErr *ujeebu.ValidationError
+
+	_, err := New(nil, Options{})
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = New(server.Client(), Options{MaxPages: -1})
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = New(server.Client(), Options{Include: []string{"("}})
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "Include", validationErr.Field)
+
+	crawler, err := New(server.Client(), Options{})
+	require.NoError(t, err)
+	handler := func(ctx context.Context, page *Page) error { return nil }
+	_, err = crawler.Run(context.Background(), nil, handler)
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = crawler.Run(context.Background(), []string{"example.com"}, handler)
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = crawler.Run(context.Background(), []string{"https://example.com/"}, nil)
+	assert.ErrorAs(t, err, &validationErr)
+	server.AssertCalls(t, "scrape", 0)
+}
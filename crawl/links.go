@@ -0,0 +1,162 @@
+package crawl
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Link is a link found in a page
+type Link struct {
+	// URL is the canonical absolute URL of the link
+	URL string
+	// Nofollow is set for links with rel="nofollow"
+	Nofollow bool
+}
+
+// trackingParams are the query parameters removed by Canonicalize, in addition to utm_*
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"msclkid": true,
+}
+
+// Canonicalize returns the canonical form of an absolute http or https URL, used to deduplicate pages.
+// The scheme and host are lowercased, default ports, fragments and tracking parameters (utm_*, fbclid,
+// gclid, msclkid) are removed, dot segments are resolved and the query parameters are sorted.
+func Canonicalize(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", err
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("unsupported URL scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("URL %q has no host", rawURL)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	u.Host = host
+	if port != "" {
+		u.Host += ":" + port
+	}
+
+	u.Path = cleanPath(u.Path)
+	u.RawPath = ""
+	u.Fragment, u.RawFragment = "", ""
+
+	if u.RawQuery != "" {
+		query := u.Query()
+		for name := range query {
+			if trackingParams[name] || strings.HasPrefix(name, "utm_") {
+				query.Del(name)
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+	u.ForceQuery = false
+	return u.String(), nil
+}
+
+// cleanPath resolves the dot segments of p, keeping its trailing slash
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	cleaned := path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+// ExtractLinks returns the canonical URLs of the <a> and <area> links of an HTML page, in document order
+// and without duplicates. Relative links are resolved against pageURL, or the <base> element of the page.
+// Links to other schemes than http and https are ignored.
+func ExtractLinks(pageURL string, body []byte) ([]Link, error) {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []Link
+	seen := map[string]bool{}
+	baseSet := false
+	z := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			// The tokenizer returns io.EOF at the end of the document, and pages are often malformed
+			return links, nil
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+
+		name, hasAttr := z.TagName()
+		tag := atom.Lookup(name)
+		if !hasAttr || (tag != atom.A && tag != atom.Area && tag != atom.Base) {
+			continue
+		}
+		var href, rel string
+		for hasAttr {
+			var key, val []byte
+			key, val, hasAttr = z.TagAttr()
+			switch string(key) {
+			case "href":
+				href = strings.TrimSpace(string(val))
+			case "rel":
+				rel = strings.ToLower(string(val))
+			}
+		}
+		if href == "" {
+			continue
+		}
+
+		if tag == atom.Base {
+			// Only the first <base> element applies
+			if !baseSet {
+				if u, err := base.Parse(href); err == nil {
+					base = u
+				}
+				baseSet = true
+			}
+			continue
+		}
+
+		ref, err := base.Parse(href)
+		if err != nil {
+			continue
+		}
+		canonical, err := Canonicalize(ref.String())
+		if err != nil || seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+		links = append(links, Link{URL: canonical, Nofollow: hasToken(rel, "nofollow")})
+	}
+}
+
+// hasToken reports whether the space-separated list contains token
+func hasToken(list, token string) bool {
+	for _, field := range strings.Fields(list) {
+		if field == token {
+			return true
+		}
+	}
+	return false
+}
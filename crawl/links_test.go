@@ -0,0 +1,79 @@
+package crawl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"https://Example.COM", "https://example.com/"},
+		{"HTTP://example.com:80/a/./b/../c", "http://example.com/a/c"},
+		{"https://example.com:443/dir/#top", "https://example.com/dir/"},
+		{"https://example.com:8443/", "https://example.com:8443/"},
+		{"https://example.com/p?b=2&a=1&utm_source=x&fbclid=y", "https://example.com/p?a=1&b=2"},
+		{"https://example.com/p?utm_medium=email", "https://example.com/p"},
+		{"https://example.com/p?", "https://example.com/p"},
+		{"https://example.com/caf%C3%A9", "https://example.com/caf%C3%A9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Canonicalize(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, invalid := range []string{"mailto:a@example.com", "/relative", "ftp://example.com", "https://"} {
+		_, err := Canonicalize(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestExtractLinks(t *testing.T) {
+	body := []byte(`<!DOCTYPE html><html><head><title>Blog</title></head><body>
+		<a href="/posts/1">One</a>
+		<a href="posts/2#comments">Two</a>
+		<a href="https://example.com/posts/1?utm_source=feed">One again</a>
+		<a href="https://other.com/" rel="external nofollow">Other</a>
+		<a href="mailto:contact@example.com">Mail</a>
+		<a href="javascript:void(0)">Menu</a>
+		<a>No href</a>
+		<map><area href="/map" /></map>
+	</body></html>`)
+
+	links, err := ExtractLinks("https://example.com/blog/", body)
+	require.NoError(t, err)
+	assert.Equal(t, []Link{
+		{URL: "https://example.com/posts/1"},
+		{URL: "https://example.com/blog/posts/2"},
+		{URL: "https://other.com/", Nofollow: true},
+		{URL: "https://example.com/map"},
+	}, links)
+}
+
+func TestExtractLinks_Base(t *testing.T) {
+	body := []byte(`<html><head><base href="https://cdn.example.com/docs/"><base href="/ignored/"></head>
+		<body><a href="intro">Intro</a><a href="../faq">FAQ</a></body></html>`)
+
+	links, err := ExtractLinks("https://example.com/", body)
+	require.NoError(t, err)
+	assert.Equal(t, []Link{
+		{URL: "https://cdn.example.com/docs/intro"},
+		{URL: "https://cdn.example.com/faq"},
+	}, links)
+}
+
+func TestExtractLinks_Malformed(t *testing.T) {
+	links, err := ExtractLinks("https://example.com/", []byte(`<a href="/a">A<div><a href='/b'`))
+	require.NoError(t, err)
+	assert.Equal(t, []Link{{URL: "https://example.com/a"}}, links)
+
+	_, err = ExtractLinks("://bad", nil)
+	assert.Error(t, err)
+}
@@ -134,9 +134,24 @@ func (c *Client) Extract(params ExtractParams) (*Article, int, error) {
 
 // ExtractWithContext calls the Ujeebu Extract API with context support
 func (c *Client) ExtractWithContext(ctx context.Context, params ExtractParams) (*Article, int, error) {
+	res, meta, err := c.ExtractWithMeta(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	return res.Article, meta.Credits, nil
+}
+
+// ExtractWithMeta calls the Ujeebu Extract API and returns the full response along with the call metadata
+func (c *Client) ExtractWithMeta(ctx context.Context, params ExtractParams) (*ExtractResponse, ResponseMeta, error) {
+	return intercept(ctx, c, "extract", params, c.extractWithMeta)
+}
+
+func (c *Client) extractWithMeta(ctx context.Context, params ExtractParams) (*ExtractResponse, ResponseMeta, error) {
+	meta := ResponseMeta{Endpoint: "extract"}
+
 	// Validate required parameters
 	if params.URL == "" {
-		return nil, 0, &ValidationError{
+		return nil, meta, &ValidationError{
 			Field:   "URL",
 			Message: "URL is required",
 		}
@@ -147,6 +162,15 @@ func (c *Client) ExtractWithContext(ctx context.Context, params ExtractParams) (
 		params.Mode = "d15de7"
 	}
 
+	cacheKey, cached, cachedMeta, hit := c.cacheLookup(ctx, "extract", params.toMap(), params.CustomHeaders)
+	if hit {
+		var r ExtractResponse
+		if err := json.Unmarshal(cached, &r); err == nil && r.Article != nil {
+			cachedMeta.ServerTime = r.Time
+			return &r, cachedMeta, nil
+		}
+	}
+
 	req := c.newRequest(ctx)
 
 	// Add custom headers (prefixed with "UJB-")
@@ -154,32 +178,33 @@ func (c *Client) ExtractWithContext(ctx context.Context, params ExtractParams) (
 		req.SetHeader("UJB-"+key, value)
 	}
 
-	req.SetResult(&ExtractResponse{}).SetError(&APIError{})
-	var resp *resty.Response
-	var err error
+	req.SetResult(&ExtractResponse{})
+	call := apiCall{
+		endpoint:   "extract",
+		method:     resty.MethodGet,
+		path:       "/extract",
+		idempotent: true,
+		metered:    true,
+	}
 
 	if params.RawHTML != "" {
 		req.SetBody(params)
 		req.SetHeader("Content-Type", "application/json")
-		resp, err = req.Post("/extract")
+		call.method = resty.MethodPost
 	} else {
 		req.SetQueryParamsFromValues(params.toMap())
-		resp, err = req.Get("/extract")
 	}
 
+	resp, meta, err := c.execute(req, call)
 	if err != nil {
-		return nil, 0, &NetworkError{Err: err}
-	}
-
-	if resp.IsError() {
-		apiErr := resp.Error().(*APIError)
-		apiErr.StatusCode = resp.StatusCode()
-		return nil, 0, apiErr
+		return nil, meta, err
 	}
 
 	res := resp.Result()
 	if r, ok := res.(*ExtractResponse); ok && r.Article != nil {
-		return r.Article, getUjeebuCreditsFromResponse(resp), nil
+		meta.ServerTime = r.Time
+		c.cacheStore("extract", cacheKey, resp.Body())
+		return r, meta, nil
 	}
-	return nil, 0, fmt.Errorf("extract API response is not a valid ExtractResponse")
+	return nil, meta, fmt.Errorf("extract API response is not a valid ExtractResponse")
 }
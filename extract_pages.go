@@ -0,0 +1,182 @@
+package ujeebu
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// DefaultMaxPages is the maximum number of pages merged by ExtractAllPages when no cap is set
+const DefaultMaxPages = 10
+
+// ExtractAllPages extracts a multi-page article and merges its pages into a single Article.
+// The first page is extracted with params; when the API returns page URLs that it did not
+// merge itself, they are extracted in order and their Text and HTML are appended, with
+// leading and trailing paragraphs repeated from the previous page (navigation, footers)
+// removed. Lines made only of markup are always kept. Images and Media are unioned.
+// maxPages caps the total number of pages (DefaultMaxPages if zero).
+// The returned credits are the total spent, including on failure.
+func (c *Client) ExtractAllPages(ctx context.Context, params ExtractParams, maxPages int) (*Article, int, error) {
+	if maxPages <= 0 {
+		maxPages = DefaultMaxPages
+	}
+
+	first, meta, err := c.ExtractWithMeta(ctx, params)
+	credits := meta.Credits
+	if err != nil {
+		return nil, credits, err
+	}
+
+	merged := *first.Article
+	merged.Images = unionStrings(nil, first.Article.Images)
+	merged.Media = unionStrings(nil, first.Article.Media)
+	merged.Pages = []string{params.URL}
+
+	seen := map[string]bool{}
+	for _, u := range []string{params.URL, first.Article.URL, first.Article.CanonicalURL} {
+		if u != "" {
+			seen[normalizePageURL(u)] = true
+		}
+	}
+
+	// Pages already merged by the API when pagination was requested
+	if first.Pagination {
+		limit := len(first.Article.Pages)
+		if params.PaginationMaxPages > 0 && params.PaginationMaxPages < limit {
+			limit = params.PaginationMaxPages
+		}
+		for _, page := range first.Article.Pages[:limit] {
+			if key := normalizePageURL(page); !seen[key] {
+				seen[key] = true
+				merged.Pages = append(merged.Pages, page)
+			}
+		}
+	}
+
+	prevText, prevHTML := merged.Text, merged.HTML
+
+	for _, page := range first.Article.Pages {
+		if len(merged.Pages) >= maxPages {
+			break
+		}
+		key := normalizePageURL(page)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if err := ctx.Err(); err != nil {
+			return nil, credits, err
+		}
+
+		pageParams := params
+		pageParams.URL = page
+		pageParams.Pagination = false
+		pageParams.PaginationMaxPages = 0
+
+		res, meta, err := c.ExtractWithMeta(ctx, pageParams)
+		credits += meta.Credits
+		if err != nil {
+			return nil, credits, err
+		}
+
+		merged.Text = appendParagraphs(merged.Text, stripRepeated(prevText, res.Article.Text))
+		merged.HTML = appendParagraphs(merged.HTML, stripRepeated(prevHTML, res.Article.HTML))
+		prevText, prevHTML = res.Article.Text, res.Article.HTML
+		merged.Images = unionStrings(merged.Images, res.Article.Images)
+		merged.Media = unionStrings(merged.Media, res.Article.Media)
+		merged.Pages = append(merged.Pages, page)
+	}
+
+	return &merged, credits, nil
+}
+
+// normalizePageURL returns a comparison key for a page URL
+func normalizePageURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return raw
+	}
+	u.Fragment = ""
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return u.String()
+}
+
+// markupOnly matches lines made only of tags, such as "<div>" or "</p></div>"
+var markupOnly = regexp.MustCompile(`^(<[^>]*>\s*)+$`)
+
+// nonEmptyLines returns the lines of s that are not blank
+func nonEmptyLines(s string) []string {
+	var lines []string
+	for _, l := range strings.Split(s, "\n") {
+		if strings.TrimSpace(l) != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+// stripRepeated returns the lines of next without the leading and trailing lines it shares
+// with prev. Lines made only of markup are kept so that HTML stays well-formed; nil is
+// returned when nothing but markup is left.
+func stripRepeated(prev, next string) []string {
+	a, b := nonEmptyLines(prev), nonEmptyLines(next)
+	same := func(i, j int) bool { return strings.TrimSpace(a[i]) == strings.TrimSpace(b[j]) }
+
+	head := 0
+	for head < len(a) && head < len(b) && same(head, head) {
+		head++
+	}
+	tail := 0
+	for tail < len(a)-head && tail < len(b)-head && same(len(a)-1-tail, len(b)-1-tail) {
+		tail++
+	}
+
+	kept := make([]string, 0, len(b))
+	content := false
+	for i, l := range b {
+		markup := markupOnly.MatchString(strings.TrimSpace(l))
+		if (i < head || i >= len(b)-tail) && !markup {
+			continue
+		}
+		content = content || !markup
+		kept = append(kept, l)
+	}
+	if !content {
+		return nil
+	}
+	return kept
+}
+
+// appendParagraphs appends lines to base as a new paragraph
+func appendParagraphs(base string, lines []string) string {
+	if len(lines) == 0 {
+		return base
+	}
+	if base == "" {
+		return strings.Join(lines, "\n")
+	}
+	return base + "\n\n" + strings.Join(lines, "\n")
+}
+
+// unionStrings appends the values of b missing from a, preserving order
+func unionStrings(a, b []string) []string {
+	seen := make(map[string]bool, len(a))
+	out := make([]string, 0, len(a)+len(b))
+	for _, v := range a {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	for _, v := range b {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,156 @@
+package ujeebu
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupMockPagesServer(t *testing.T, pages map[string]map[string]any) (*httptest.Server, *Client, *int32) {
+	var calls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		page, ok := pages[r.URL.Query().Get("url")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Not found"}`))
+			return
+		}
+		w.Header().Set(CreditsHeader, "10")
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	t.Cleanup(mockServer.Close)
+
+	client, err := NewClient("test_api_key", WithBaseURL(mockServer.URL))
+	require.NoError(t, err)
+	return mockServer, client, &calls
+}
+
+func TestExtractAllPages_MergesPages(t *testing.T) {
+	pageURLs := []string{"https://example.com/story", "https://example.com/story/2", "https://example.com/story/3"}
+	_, client, calls := setupMockPagesServer(t, map[string]map[string]any{
+		"https://example.com/story": {"article": map[string]any{
+			"url":    "https://example.com/story",
+			"title":  "Story",
+			"text":   "Intro paragraph.\nSubscribe to our newsletter",
+			"html":   "<p>Intro paragraph.</p>",
+			"images": []string{"a.jpg", "b.jpg"},
+			"pages":  pageURLs,
+		}},
+		"https://example.com/story/2": {"article": map[string]any{
+			"text":   "Second paragraph.\nSubscribe to our newsletter",
+			"html":   "<p>Second paragraph.</p>",
+			"images": []string{"b.jpg", "c.jpg"},
+			"media":  []string{"video.mp4"},
+		}},
+		"https://example.com/story/3": {"article": map[string]any{
+			"text": "Third paragraph.",
+			"html": "<p>Third paragraph.</p>",
+		}},
+	})
+
+	article, credits, err := client.ExtractAllPages(context.Background(), ExtractParams{URL: "https://example.com/story"}, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Story", article.Title)
+	assert.Equal(t, "Intro paragraph.\nSubscribe to our newsletter\n\nSecond paragraph.\n\nThird paragraph.", article.Text)
+	assert.Equal(t, "<p>Intro paragraph.</p>\n\n<p>Second paragraph.</p>\n\n<p>Third paragraph.</p>", article.HTML)
+	assert.Equal(t, []string{"a.jpg", "b.jpg", "c.jpg"}, article.Images)
+	assert.Equal(t, []string{"video.mp4"}, article.Media)
+	assert.Equal(t, pageURLs, article.Pages)
+	assert.Equal(t, 30, credits)
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+}
+
+func TestExtractAllPages_KeepsMarkupAndRepeatsWithinPage(t *testing.T) {
+	_, client, _ := setupMockPagesServer(t, map[string]map[string]any{
+		"https://example.com/story": {"article": map[string]any{
+			"text":  "Menu\nOne.\nChorus.\nChorus.\nFooter",
+			"html":  "<div>\n<nav>Menu</nav>\n<div>\n<p>One</p>\n</div>\n<footer>Footer</footer>\n</div>",
+			"pages": []string{"https://example.com/story/2"},
+		}},
+		"https://example.com/story/2": {"article": map[string]any{
+			"text": "Menu\nTwo.\nChorus.\nChorus.\nFooter",
+			"html": "<div>\n<nav>Menu</nav>\n<div>\n<p>Two</p>\n</div>\n<footer>Footer</footer>\n</div>",
+		}},
+	})
+
+	article, _, err := client.ExtractAllPages(context.Background(), ExtractParams{URL: "https://example.com/story"}, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Menu\nOne.\nChorus.\nChorus.\nFooter\n\nTwo.", article.Text)
+	assert.Equal(t, "<div>\n<nav>Menu</nav>\n<div>\n<p>One</p>\n</div>\n<footer>Footer</footer>\n</div>\n\n<div>\n<div>\n<p>Two</p>\n</div>\n</div>", article.HTML)
+
+	// Every opened element is closed, in order
+	decoder := xml.NewDecoder(strings.NewReader("<root>" + article.HTML + "</root>"))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+	}
+}
+
+func TestExtractAllPages_SkipsPagesMergedByAPI(t *testing.T) {
+	_, client, calls := setupMockPagesServer(t, map[string]map[string]any{
+		"https://example.com/story": {
+			"pagination": true,
+			"article": map[string]any{
+				"url":   "https://example.com/story",
+				"text":  "Page one.\nPage two.",
+				"pages": []string{"https://example.com/story/", "https://example.com/story/2", "https://example.com/story/3"},
+			},
+		},
+		"https://example.com/story/3": {"article": map[string]any{"text": "Page three."}},
+	})
+
+	params := ExtractParams{URL: "https://example.com/story", Pagination: true, PaginationMaxPages: 2}
+	article, credits, err := client.ExtractAllPages(context.Background(), params, 5)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Page one.\nPage two.\n\nPage three.", article.Text)
+	assert.Equal(t, 20, credits)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestExtractAllPages_RespectsCap(t *testing.T) {
+	_, client, calls := setupMockPagesServer(t, map[string]map[string]any{
+		"https://example.com/story": {"article": map[string]any{
+			"text":  "One.",
+			"pages": []string{"https://example.com/story", "https://example.com/story/2", "https://example.com/story/3"},
+		}},
+		"https://example.com/story/2": {"article": map[string]any{"text": "Two."}},
+	})
+
+	article, _, err := client.ExtractAllPages(context.Background(), ExtractParams{URL: "https://example.com/story"}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "One.\n\nTwo.", article.Text)
+	assert.Len(t, article.Pages, 2)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestExtractAllPages_PageError(t *testing.T) {
+	_, client, _ := setupMockPagesServer(t, map[string]map[string]any{
+		"https://example.com/story": {"article": map[string]any{
+			"text":  "One.",
+			"pages": []string{"https://example.com/story/2"},
+		}},
+	})
+
+	article, credits, err := client.ExtractAllPages(context.Background(), ExtractParams{URL: "https://example.com/story"}, 0)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Nil(t, article)
+	assert.Equal(t, 10, credits)
+}
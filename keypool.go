@@ -0,0 +1,341 @@
+package ujeebu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoAvailableKey is matched by errors.Is when every key of a KeyPool is exhausted
+var ErrNoAvailableKey = errors.New("ujeebu: no API key available in the key pool")
+
+// KeyPoolExhaustedError is returned when a call could not be made with any key of a KeyPool
+type KeyPoolExhaustedError struct {
+	// Keys is the number of keys in the pool
+	Keys int
+	// Err is the error returned with the last key tried, nil if no key was available
+	Err error
+}
+
+// Error implements the error interface
+func (e *KeyPoolExhaustedError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%v (%d keys): %v", ErrNoAvailableKey, e.Keys, e.Err)
+	}
+	return fmt.Sprintf("%v (%d keys)", ErrNoAvailableKey, e.Keys)
+}
+
+// Is makes errors.Is(err, ErrNoAvailableKey) match
+func (e *KeyPoolExhaustedError) Is(target error) bool {
+	return target == ErrNoAvailableKey
+}
+
+// Unwrap returns the error returned with the last key tried
+func (e *KeyPoolExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+// PoolKey is an API key of a KeyPool
+type PoolKey struct {
+	// Key is the API key
+	Key string
+	// Weight is the relative share of calls made with the key, 1 if zero
+	Weight int
+}
+
+// KeyPoolOptions configures a KeyPool
+type KeyPoolOptions struct {
+	// Cooldown is how long an exhausted key is left out before being tried again.
+	// If zero, the key is left out until a Refresh reports a positive balance or Reset is called.
+	Cooldown time.Duration
+	// RefreshInterval is how often the balance of each key is re-read from the Account API
+	// in the background while the pool is used by a client, 0 to disable
+	RefreshInterval time.Duration
+}
+
+// KeyStatus is a snapshot of the state of a key in a KeyPool
+type KeyStatus struct {
+	// Key is the API key
+	Key string
+	// Weight is the relative share of calls made with the key
+	Weight int
+	// Exhausted is true while the key is left out of the rotation
+	Exhausted bool
+	// Err is the last error returned with the key, if any
+	Err error
+	// Requests is the number of calls made with the key
+	Requests int
+	// Credits is the number of credits charged to the key
+	Credits int
+	// Balance is the known balance of the key, -1 if it has not been read yet
+	Balance int
+	// Account is the last account information read for the key, if any
+	Account *AccountResponse
+	// RefreshedAt is when the account information was last read
+	RefreshedAt time.Time
+}
+
+// KeyPool spreads calls over several API keys using weighted round-robin.
+// Keys returning 401, 402 or quota errors, or whose known balance is spent, are
+// marked exhausted and calls fail over to the next key. It is safe for concurrent use.
+type KeyPool struct {
+	mu         sync.Mutex
+	keys       []*poolKey
+	byKey      map[string]*poolKey
+	options    KeyPoolOptions
+	refreshing bool
+	refreshed  time.Time
+}
+
+type poolKey struct {
+	key         string
+	weight      int
+	current     int
+	exhausted   bool
+	exhaustedAt time.Time
+	err         error
+	requests    int
+	credits     int
+	balance     int
+	account     *AccountResponse
+	refreshedAt time.Time
+}
+
+// NewKeyPool creates a pool of API keys
+func NewKeyPool(keys []PoolKey, options KeyPoolOptions) (*KeyPool, error) {
+	if len(keys) == 0 {
+		return nil, &ValidationError{Field: "keys", Message: "at least one key is required"}
+	}
+
+	p := &KeyPool{byKey: make(map[string]*poolKey, len(keys)), options: options}
+	for _, k := range keys {
+		if k.Key == "" {
+			return nil, &ValidationError{Field: "keys", Message: "keys cannot be empty"}
+		}
+		if k.Weight < 0 {
+			return nil, &ValidationError{Field: "keys", Message: "weights cannot be negative"}
+		}
+		if _, ok := p.byKey[k.Key]; ok {
+			return nil, &ValidationError{Field: "keys", Message: "duplicate key"}
+		}
+		weight := k.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		pk := &poolKey{key: k.Key, weight: weight, balance: -1}
+		p.keys = append(p.keys, pk)
+		p.byKey[k.Key] = pk
+	}
+	return p, nil
+}
+
+// WithKeyPool makes the client send each call with a key of the pool, failing
+// over to the next key when a key is exhausted
+func WithKeyPool(pool *KeyPool) ClientOption {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, pool.middleware(c))
+	}
+}
+
+// Status returns a snapshot of the state of every key, in pool order
+func (p *KeyPool) Status() []KeyStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status := make([]KeyStatus, len(p.keys))
+	for i, k := range p.keys {
+		status[i] = KeyStatus{
+			Key:         k.key,
+			Weight:      k.weight,
+			Exhausted:   k.exhausted,
+			Err:         k.err,
+			Requests:    k.requests,
+			Credits:     k.credits,
+			Balance:     k.balance,
+			Account:     k.account,
+			RefreshedAt: k.refreshedAt,
+		}
+	}
+	return status
+}
+
+// Reset puts every exhausted key back into the rotation
+func (p *KeyPool) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, k := range p.keys {
+		k.exhausted = false
+		k.err = nil
+	}
+}
+
+// Refresh reads the account information of every key with client.
+// Keys with a positive balance are put back into the rotation.
+func (p *KeyPool) Refresh(ctx context.Context, client *Client) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	p.mu.Lock()
+	keys := make([]string, len(p.keys))
+	for i, k := range p.keys {
+		keys[i] = k.key
+	}
+	p.mu.Unlock()
+
+	var errs []error
+	for _, key := range keys {
+		account, meta, err := client.AccountWithMeta(context.WithValue(ctx, apiKeyKey{}, key))
+		p.observe(key, account, meta, err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("refreshing key %s: %w", maskKey(key), err))
+		}
+	}
+
+	p.mu.Lock()
+	p.refreshed = time.Now()
+	p.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+// middleware sends calls with the keys of the pool.
+// Calls already bound to a key, such as the ones made by Refresh, are passed through.
+func (p *KeyPool) middleware(c *Client) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (any, ResponseMeta, error) {
+			if call.APIKey != "" || apiKeyFromContext(ctx) != "" {
+				return next(ctx, call)
+			}
+			p.refreshInBackground(c)
+
+			var lastErr error
+			meta := ResponseMeta{Endpoint: call.Endpoint}
+			for range p.keys {
+				key := p.pick(time.Now())
+				if key == "" {
+					break
+				}
+				call.APIKey = key
+				res, m, err := next(ctx, call)
+				if !p.observe(key, res, m, err) {
+					return res, m, err
+				}
+				lastErr, meta = err, m
+				if ctx.Err() != nil {
+					break
+				}
+			}
+			return nil, meta, &KeyPoolExhaustedError{Keys: len(p.keys), Err: lastErr}
+		}
+	}
+}
+
+// pick returns the next available key using smooth weighted round-robin, "" if none is available
+func (p *KeyPool) pick(now time.Time) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var best *poolKey
+	total := 0
+	for _, k := range p.keys {
+		if k.exhausted {
+			if p.options.Cooldown <= 0 || now.Sub(k.exhaustedAt) < p.options.Cooldown {
+				continue
+			}
+			k.exhausted = false
+		}
+		k.current += k.weight
+		total += k.weight
+		if best == nil || k.current > best.current {
+			best = k
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	best.current -= total
+	return best.key
+}
+
+// observe records the outcome of a call made with key and reports whether the key is exhausted
+func (p *KeyPool) observe(key string, res any, meta ResponseMeta, err error) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	k, ok := p.byKey[key]
+	if !ok {
+		return false
+	}
+	now := time.Now()
+	k.requests++
+	k.credits += meta.Credits
+
+	if err != nil {
+		k.err = err
+		if isKeyExhaustedError(err) {
+			k.exhausted, k.exhaustedAt = true, now
+			return true
+		}
+		return false
+	}
+
+	if account, ok := res.(*AccountResponse); ok && account != nil {
+		k.account, k.balance, k.refreshedAt = account, account.Balance, now
+		if k.balance > 0 {
+			k.exhausted, k.err = false, nil
+		}
+	} else if k.balance >= 0 {
+		k.balance = max(k.balance-meta.Credits, 0)
+	}
+	if k.balance == 0 {
+		k.exhausted, k.exhaustedAt = true, now
+	}
+	return false
+}
+
+// refreshInBackground starts a Refresh when the RefreshInterval has elapsed
+func (p *KeyPool) refreshInBackground(c *Client) {
+	if p.options.RefreshInterval <= 0 {
+		return
+	}
+	p.mu.Lock()
+	if p.refreshing || time.Since(p.refreshed) < p.options.RefreshInterval {
+		p.mu.Unlock()
+		return
+	}
+	p.refreshing = true
+	p.mu.Unlock()
+
+	go func() {
+		err := p.Refresh(context.Background(), c)
+		if err != nil && c.logger != nil {
+			c.logger.Printf("ujeebu: failed to refresh key pool balances: %v", redactText(err.Error()))
+		}
+		p.mu.Lock()
+		p.refreshing = false
+		p.mu.Unlock()
+	}()
+}
+
+// isKeyExhaustedError reports whether err means the key cannot be used anymore
+func isKeyExhaustedError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.IsUnauthorized() || apiErr.StatusCode == http.StatusPaymentRequired {
+		return true
+	}
+	return strings.Contains(strings.ToLower(apiErr.Message+" "+apiErr.errorCodeString()), "quota")
+}
+
+// maskKey hides all but the last 4 characters of an API key
+func maskKey(key string) string {
+	if len(key) <= 4 {
+		return strings.Repeat("*", len(key))
+	}
+	return strings.Repeat("*", len(key)-4) + key[len(key)-4:]
+}
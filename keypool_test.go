@@ -0,0 +1,209 @@
+package ujeebu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupMockKeyPoolServer answers card and account calls with the status and balance configured for each key
+func setupMockKeyPoolServer(t *testing.T, statuses map[string]int, balances map[string]int) (*httptest.Server, *[]string) {
+	var mu sync.Mutex
+	var keys []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("ApiKey")
+		mu.Lock()
+		keys = append(keys, key)
+		status := statuses[key]
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if status != 0 && r.URL.Path != "/account" {
+			w.WriteHeader(status)
+			_ = json.NewEncoder(w).Encode(APIError{Message: "key error"})
+			return
+		}
+		if r.URL.Path == "/account" {
+			_ = json.NewEncoder(w).Encode(AccountResponse{Balance: balances[key]})
+			return
+		}
+		w.Header().Set(CreditsHeader, "2")
+		_ = json.NewEncoder(w).Encode(CardResponse{Title: key})
+	}))
+	t.Cleanup(mockServer.Close)
+	return mockServer, &keys
+}
+
+func newTestKeyPool(t *testing.T, options KeyPoolOptions, keys ...PoolKey) *KeyPool {
+	pool, err := NewKeyPool(keys, options)
+	require.NoError(t, err)
+	return pool
+}
+
+func TestNewKeyPool_Validation(t *testing.T) {
+	for _, keys := range [][]PoolKey{
+		nil,
+		{{Key: ""}},
+		{{Key: "a", Weight: -1}},
+		{{Key: "a"}, {Key: "a"}},
+	} {
+		_, err := NewKeyPool(keys, KeyPoolOptions{})
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	}
+}
+
+func TestKeyPool_Pick(t *testing.T) {
+	pool := newTestKeyPool(t, KeyPoolOptions{}, PoolKey{Key: "a"}, PoolKey{Key: "b"}, PoolKey{Key: "c"})
+	var picked []string
+	for range 6 {
+		picked = append(picked, pool.pick(time.Now()))
+	}
+	assert.Equal(t, []string{"a", "b", "c", "a", "b", "c"}, picked)
+
+	weighted := newTestKeyPool(t, KeyPoolOptions{}, PoolKey{Key: "a", Weight: 3}, PoolKey{Key: "b", Weight: 1})
+	counts := make(map[string]int)
+	for range 8 {
+		counts[weighted.pick(time.Now())]++
+	}
+	assert.Equal(t, map[string]int{"a": 6, "b": 2}, counts)
+}
+
+func TestKeyPool_Failover(t *testing.T) {
+	mockServer, keys := setupMockKeyPoolServer(t,
+		map[string]int{"key_a": http.StatusUnauthorized, "key_b": http.StatusPaymentRequired},
+		nil)
+	pool := newTestKeyPool(t, KeyPoolOptions{}, PoolKey{Key: "key_a"}, PoolKey{Key: "key_b"}, PoolKey{Key: "key_c"})
+	client, err := NewClient("key_a", WithBaseURL(mockServer.URL), WithKeyPool(pool))
+	require.NoError(t, err)
+
+	card, credits, err := client.Card(CardParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "key_c", card.Title)
+	assert.Equal(t, 2, credits)
+
+	// Exhausted keys are no longer tried
+	card, _, err = client.Card(CardParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "key_c", card.Title)
+	assert.Equal(t, []string{"key_a", "key_b", "key_c", "key_c"}, *keys)
+
+	status := pool.Status()
+	assert.True(t, status[0].Exhausted)
+	assert.True(t, status[1].Exhausted)
+	assert.False(t, status[2].Exhausted)
+	assert.Equal(t, 2, status[2].Requests)
+	assert.Equal(t, 4, status[2].Credits)
+	assert.Equal(t, -1, status[2].Balance)
+	var apiErr *APIError
+	require.ErrorAs(t, status[0].Err, &apiErr)
+	assert.True(t, apiErr.IsUnauthorized())
+}
+
+func TestKeyPool_AllExhausted(t *testing.T) {
+	mockServer, keys := setupMockKeyPoolServer(t,
+		map[string]int{"key_a": http.StatusUnauthorized, "key_b": http.StatusUnauthorized},
+		nil)
+	pool := newTestKeyPool(t, KeyPoolOptions{}, PoolKey{Key: "key_a"}, PoolKey{Key: "key_b"})
+	client, err := NewClient("key_a", WithBaseURL(mockServer.URL), WithKeyPool(pool))
+	require.NoError(t, err)
+
+	_, _, err = client.Card(CardParams{URL: "https://example.com"})
+	assert.ErrorIs(t, err, ErrNoAvailableKey)
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+
+	_, _, err = client.Card(CardParams{URL: "https://example.com"})
+	var poolErr *KeyPoolExhaustedError
+	require.ErrorAs(t, err, &poolErr)
+	assert.Nil(t, poolErr.Err)
+	assert.Len(t, *keys, 2)
+
+	pool.Reset()
+	assert.False(t, pool.Status()[0].Exhausted)
+}
+
+func TestKeyPool_QuotaError(t *testing.T) {
+	assert.True(t, isKeyExhaustedError(&APIError{StatusCode: http.StatusTooManyRequests, Message: "Monthly quota exceeded"}))
+	assert.True(t, isKeyExhaustedError(&APIError{StatusCode: http.StatusForbidden, ErrorCode: "QUOTA_EXCEEDED"}))
+	assert.False(t, isKeyExhaustedError(&APIError{StatusCode: http.StatusTooManyRequests, Message: "Too many requests"}))
+	assert.False(t, isKeyExhaustedError(errors.New("quota")))
+}
+
+func TestKeyPool_RefreshAndBalance(t *testing.T) {
+	mockServer, _ := setupMockKeyPoolServer(t, nil, map[string]int{"key_a": 3, "key_b": 0})
+	pool := newTestKeyPool(t, KeyPoolOptions{}, PoolKey{Key: "key_a"}, PoolKey{Key: "key_b"})
+	client, err := NewClient("key_a", WithBaseURL(mockServer.URL), WithKeyPool(pool))
+	require.NoError(t, err)
+
+	require.NoError(t, pool.Refresh(context.Background(), client))
+	status := pool.Status()
+	assert.Equal(t, 3, status[0].Balance)
+	assert.NotNil(t, status[0].Account)
+	assert.False(t, status[0].RefreshedAt.IsZero())
+	assert.True(t, status[1].Exhausted)
+
+	// Each card call costs 2 credits: the second one spends the balance of key_a
+	for range 2 {
+		card, _, err := client.Card(CardParams{URL: "https://example.com"})
+		require.NoError(t, err)
+		assert.Equal(t, "key_a", card.Title)
+	}
+	assert.True(t, pool.Status()[0].Exhausted)
+
+	_, _, err = client.Card(CardParams{URL: "https://example.com"})
+	assert.ErrorIs(t, err, ErrNoAvailableKey)
+}
+
+func TestKeyPool_Cooldown(t *testing.T) {
+	pool := newTestKeyPool(t, KeyPoolOptions{Cooldown: time.Minute}, PoolKey{Key: "a"})
+	now := time.Now()
+	pool.observe("a", nil, ResponseMeta{}, &APIError{StatusCode: http.StatusUnauthorized})
+
+	assert.Empty(t, pool.pick(now))
+	assert.Equal(t, "a", pool.pick(now.Add(2*time.Minute)))
+}
+
+func TestKeyPool_BackgroundRefresh(t *testing.T) {
+	mockServer, _ := setupMockKeyPoolServer(t, nil, map[string]int{"key_a": 100})
+	pool := newTestKeyPool(t, KeyPoolOptions{RefreshInterval: time.Hour}, PoolKey{Key: "key_a"})
+	client, err := NewClient("key_a", WithBaseURL(mockServer.URL), WithKeyPool(pool))
+	require.NoError(t, err)
+
+	_, _, err = client.Card(CardParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return pool.Status()[0].Account != nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestKeyPool_Concurrent(t *testing.T) {
+	mockServer, keys := setupMockKeyPoolServer(t, nil, nil)
+	pool := newTestKeyPool(t, KeyPoolOptions{}, PoolKey{Key: "key_a"}, PoolKey{Key: "key_b"})
+	client, err := NewClient("key_a", WithBaseURL(mockServer.URL), WithKeyPool(pool))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := client.Card(CardParams{URL: "https://example.com"})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, *keys, 20)
+	status := pool.Status()
+	assert.Equal(t, 10, status[0].Requests)
+	assert.Equal(t, 10, status[1].Requests)
+}
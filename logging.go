@@ -0,0 +1,198 @@
+package ujeebu
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Redacted replaces secrets in logs
+const Redacted = "REDACTED"
+
+// redactedParams are the request parameters never logged in clear
+var redactedParams = []string{"custom_proxy_password", "cookies"}
+
+// redactedHeaders are the request headers never logged in clear, besides the UJB- ones
+var redactedHeaders = []string{"ApiKey", "Cookie", "Set-Cookie", "Authorization", "Proxy-Authorization"}
+
+var (
+	queryParamPattern = regexp.MustCompile(`(?i)\b(custom_proxy_password|cookies|apikey)=[^&\s"']*`)
+	jsonParamPattern  = regexp.MustCompile(`(?i)"(custom_proxy_password|cookies|apikey)"\s*:\s*"(?:[^"\\]|\\.)*"`)
+)
+
+// WithSlogLogger sets a structured logger receiving request, response and retry events.
+// API keys, proxy passwords, cookies and UJB- headers are always redacted.
+func WithSlogLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.slog = logger
+	}
+}
+
+// isSensitiveHeader reports whether the value of a request header must be redacted
+func isSensitiveHeader(name string) bool {
+	if len(name) >= 4 && strings.EqualFold(name[:4], "UJB-") {
+		return true
+	}
+	for _, h := range redactedHeaders {
+		if strings.EqualFold(name, h) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHeaders returns a copy of h with the sensitive values redacted
+func redactHeaders(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for name, values := range h {
+		if isSensitiveHeader(name) {
+			out[name] = []string{Redacted}
+			continue
+		}
+		out[name] = append([]string(nil), values...)
+	}
+	return out
+}
+
+// redactQuery returns a copy of q with the sensitive values redacted
+func redactQuery(q url.Values) url.Values {
+	out := make(url.Values, len(q))
+	for name, values := range q {
+		out[name] = append([]string(nil), values...)
+	}
+	for _, name := range redactedParams {
+		if _, ok := out[name]; ok {
+			out[name] = []string{Redacted}
+		}
+	}
+	return out
+}
+
+// redactText redacts secrets in free text such as error messages and debug dumps
+func redactText(s string) string {
+	s = queryParamPattern.ReplaceAllString(s, "${1}="+Redacted)
+	return jsonParamPattern.ReplaceAllString(s, `"${1}":"`+Redacted+`"`)
+}
+
+// logAttempt logs the outcome of an attempt: the response at debug level,
+// or the error at warn level when the call is retried and error level otherwise
+func (c *Client) logAttempt(ctx context.Context, req *resty.Request, call apiCall, attempt int, meta ResponseMeta, err error, retry bool, delay time.Duration) {
+	if c.slog == nil {
+		return
+	}
+
+	level := slog.LevelDebug
+	msg := "ujeebu response"
+	switch {
+	case err != nil && retry:
+		level, msg = slog.LevelWarn, "ujeebu request failed, retrying"
+	case err != nil:
+		level, msg = slog.LevelError, "ujeebu request failed"
+	}
+	if !c.slog.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("endpoint", call.endpoint),
+		slog.String("method", call.method),
+		slog.String("url", c.logURL(req, call)),
+		slog.Int("attempt", attempt),
+	}
+	if len(req.Header) > 0 {
+		attrs = append(attrs, slog.Any("headers", redactHeaders(req.Header)))
+	}
+	if meta.StatusCode != 0 {
+		attrs = append(attrs, slog.Int("status", meta.StatusCode))
+	}
+	attrs = append(attrs, slog.Duration("duration", meta.Duration))
+	if meta.Credits != 0 {
+		attrs = append(attrs, slog.Int("credits", meta.Credits))
+	}
+	if meta.RequestID != "" {
+		attrs = append(attrs, slog.String("request_id", meta.RequestID))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", redactText(err.Error())))
+	}
+	if retry {
+		attrs = append(attrs, slog.Duration("delay", delay))
+	}
+	c.slog.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// logRequest logs call at debug level before it is sent
+func (c *Client) logRequest(ctx context.Context, req *resty.Request, call apiCall, attempt int) {
+	if c.slog == nil || !c.slog.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	c.slog.LogAttrs(ctx, slog.LevelDebug, "ujeebu request",
+		slog.String("endpoint", call.endpoint),
+		slog.String("method", call.method),
+		slog.String("url", c.logURL(req, call)),
+		slog.Int("attempt", attempt),
+	)
+}
+
+// logURL returns the redacted URL of req
+func (c *Client) logURL(req *resty.Request, call apiCall) string {
+	target := c.baseURL + call.path
+	if len(req.QueryParam) > 0 {
+		target += "?" + redactQuery(req.QueryParam).Encode()
+	}
+	return target
+}
+
+// restyLogger routes the HTTP client logs, including WithDebug dumps, to the client loggers with secrets redacted
+type restyLogger struct {
+	c *Client
+}
+
+func (l restyLogger) Errorf(format string, v ...interface{}) {
+	l.log(slog.LevelError, "ERROR", format, v...)
+}
+
+func (l restyLogger) Warnf(format string, v ...interface{}) {
+	l.log(slog.LevelWarn, "WARN", format, v...)
+}
+
+func (l restyLogger) Debugf(format string, v ...interface{}) {
+	l.log(slog.LevelDebug, "DEBUG", format, v...)
+}
+
+func (l restyLogger) log(level slog.Level, prefix, format string, v ...interface{}) {
+	msg := redactText(fmt.Sprintf(format, v...))
+	switch {
+	case l.c.slog != nil:
+		// Failures are already reported by the client's own events
+		if level > slog.LevelDebug {
+			level = slog.LevelDebug
+		}
+		l.c.slog.Log(context.Background(), level, msg, slog.String("component", "resty"))
+	case l.c.logger != nil:
+		l.c.logger.Printf("%s RESTY %s", prefix, msg)
+	}
+}
+
+// redactRequestLog redacts the headers and body of WithDebug request dumps
+func redactRequestLog(rl *resty.RequestLog) error {
+	rl.Header = redactHeaders(rl.Header)
+	rl.Body = redactText(rl.Body)
+	return nil
+}
+
+// redactResponseLog redacts the cookies of WithDebug response dumps
+func redactResponseLog(rl *resty.ResponseLog) error {
+	if rl.Header.Get("Set-Cookie") != "" {
+		rl.Header = rl.Header.Clone()
+		rl.Header.Set("Set-Cookie", Redacted)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package ujeebu
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	secretKey      = "secret_api_key"
+	secretPassword = "proxy_s3cret"
+	secretCookie   = "session=c00kie"
+	secretToken    = "Bearer t0ken"
+)
+
+// setupMockLoggingServer fails the first failures requests with a 503
+func setupMockLoggingServer(t *testing.T, failures int, opts ...ClientOption) *Client {
+	calls := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if calls <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"message": "unavailable"}`))
+			return
+		}
+		w.Header().Set(CreditsHeader, "5")
+		w.Header().Set("Set-Cookie", secretCookie)
+		_, _ = w.Write([]byte(`{"success": true, "html": "<p>ok</p>"}`))
+	}))
+	t.Cleanup(mockServer.Close)
+
+	client, err := NewClient(secretKey, append([]ClientOption{WithBaseURL(mockServer.URL)}, opts...)...)
+	require.NoError(t, err)
+	return client
+}
+
+func secretScrapeParams() ScrapeParams {
+	return ScrapeParams{
+		URL:                 "https://example.com",
+		Cookies:             secretCookie,
+		CustomProxy:         "proxy.example.com",
+		CustomProxyPassword: secretPassword,
+		CustomHeaders:       map[string]string{"Authorization": secretToken},
+	}
+}
+
+func assertNoSecrets(t *testing.T, output string) {
+	t.Helper()
+	for _, secret := range []string{secretKey, secretPassword, "c00kie", "t0ken"} {
+		assert.NotContains(t, output, secret)
+	}
+	assert.Contains(t, output, Redacted)
+}
+
+func decodeLogs(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestSlogLogger_Events(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := setupMockLoggingServer(t, 1,
+		WithSlogLogger(logger),
+		WithRetryPolicy(&BackoffPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}),
+	)
+
+	_, _, err := client.ScrapeWithMeta(context.WithValue(context.Background(), apiKeyKey{}, secretKey), secretScrapeParams())
+	require.NoError(t, err)
+	assertNoSecrets(t, buf.String())
+
+	records := decodeLogs(t, &buf)
+	require.Len(t, records, 4)
+	assert.Equal(t, "ujeebu request", records[0]["msg"])
+	assert.Equal(t, "DEBUG", records[0]["level"])
+	assert.Equal(t, "ujeebu request failed, retrying", records[1]["msg"])
+	assert.Equal(t, "WARN", records[1]["level"])
+	assert.EqualValues(t, http.StatusServiceUnavailable, records[1]["status"])
+	assert.Equal(t, "ujeebu response", records[3]["msg"])
+	assert.Equal(t, "DEBUG", records[3]["level"])
+	assert.Equal(t, "scrape", records[3]["endpoint"])
+	assert.EqualValues(t, 2, records[3]["attempt"])
+	assert.EqualValues(t, 5, records[3]["credits"])
+
+	u, err := url.Parse(records[3]["url"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, Redacted, u.Query().Get("custom_proxy_password"))
+	assert.Equal(t, "proxy.example.com", u.Query().Get("custom_proxy"))
+}
+
+func TestSlogLogger_Failure(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	client := setupMockLoggingServer(t, 1, WithSlogLogger(logger))
+
+	_, _, err := client.Scrape(secretScrapeParams())
+	require.Error(t, err)
+
+	records := decodeLogs(t, &buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "ujeebu request failed", records[0]["msg"])
+	assert.Equal(t, "ERROR", records[0]["level"])
+	assert.Contains(t, records[0]["error"], "unavailable")
+	assert.NotContains(t, buf.String(), secretPassword)
+}
+
+type bufferLogger struct {
+	buf bytes.Buffer
+}
+
+func (l *bufferLogger) Printf(format string, v ...interface{}) {
+	fmt.Fprintf(&l.buf, format+"\n", v...)
+}
+
+func TestDebug_Redacted(t *testing.T) {
+	logger := &bufferLogger{}
+	client := setupMockLoggingServer(t, 0, WithDebug(true), WithLogger(logger))
+
+	_, _, err := client.Scrape(secretScrapeParams())
+	require.NoError(t, err)
+
+	output := logger.buf.String()
+	assert.Contains(t, output, "~~~ REQUEST ~~~")
+	assert.Contains(t, output, "~~~ RESPONSE ~~~")
+	assertNoSecrets(t, output)
+}
+
+func TestRedactText(t *testing.T) {
+	err := &url.Error{Op: "Get", URL: "https://api.ujeebu.com/scrape?cookies=a%3Db&custom_proxy_password=pw&url=x", Err: assert.AnError}
+	assert.Equal(t,
+		`Get "https://api.ujeebu.com/scrape?cookies=REDACTED&custom_proxy_password=REDACTED&url=x": `+assert.AnError.Error(),
+		redactText(err.Error()))
+	assert.Equal(t, `{"url":"x","custom_proxy_password":"REDACTED"}`, redactText(`{"url":"x","custom_proxy_password":"p\"w"}`))
+
+	headers := redactHeaders(http.Header{"Apikey": {"k"}, "Ujb-Authorization": {"t"}, "Content-Type": {"application/json"}})
+	assert.Equal(t, http.Header{"Apikey": {Redacted}, "Ujb-Authorization": {Redacted}, "Content-Type": {"application/json"}}, headers)
+}
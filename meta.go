@@ -0,0 +1,51 @@
+package ujeebu
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// requestIDHeaders lists the response headers that may carry a request identifier
+var requestIDHeaders = []string{"ujb-request-id", "x-request-id"}
+
+// ResponseMeta holds metadata about a completed API call.
+// It is returned by the ...WithMeta methods, including on API errors when a response was received.
+type ResponseMeta struct {
+	// Endpoint is the API endpoint name (extract, scrape, card, serp, account)
+	Endpoint string `json:"endpoint"`
+	// Credits is the number of credits charged across all attempts, from the ujb-credits header
+	Credits int `json:"credits"`
+	// StatusCode is the HTTP status code of the last attempt
+	StatusCode int `json:"status_code"`
+	// Duration is the total time spent on the call, including retries
+	Duration time.Duration `json:"duration"`
+	// Attempts is the number of requests sent
+	Attempts int `json:"attempts"`
+	// RequestID is the request identifier returned by the API, if any
+	RequestID string `json:"request_id,omitempty"`
+	// ServerTime is the processing time in seconds reported in the response body, if any
+	ServerTime float64 `json:"server_time,omitempty"`
+	// Headers are the raw response headers of the last attempt
+	Headers http.Header `json:"headers,omitempty"`
+	// Cached is true when the response was served from the client cache without calling the API
+	Cached bool `json:"cached,omitempty"`
+}
+
+// fill records the response details of an attempt
+func (m *ResponseMeta) fill(resp *resty.Response) {
+	m.StatusCode, m.Headers, m.RequestID = 0, nil, ""
+	if resp == nil || resp.RawResponse == nil {
+		return
+	}
+	m.StatusCode = resp.StatusCode()
+	m.Headers = resp.Header()
+	m.Credits += getUjeebuCreditsFromResponse(resp)
+	for _, name := range requestIDHeaders {
+		if id := m.Headers.Get(name); id != "" {
+			m.RequestID = id
+			break
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package ujeebu
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractWithMeta(t *testing.T) {
+	mockResponse := `{
+		"article": {"url": "https://example.com/article", "title": "Sample Title"},
+		"time": 1.25,
+		"js": true,
+		"pagination": true
+	}`
+
+	mockServer, client := setupMockExtractServer(mockResponse, map[string]string{
+		CreditsHeader:  "10",
+		"X-Request-Id": "req-123",
+	}, http.StatusOK)
+	defer mockServer.Close()
+
+	res, meta, err := client.ExtractWithMeta(context.Background(), ExtractParams{URL: "https://example.com/article"})
+	require.NoError(t, err)
+	assert.Equal(t, "Sample Title", res.Article.Title)
+	assert.Equal(t, 1.25, res.Time)
+	assert.True(t, res.JS)
+	assert.True(t, res.Pagination)
+
+	assert.Equal(t, "extract", meta.Endpoint)
+	assert.Equal(t, 10, meta.Credits)
+	assert.Equal(t, http.StatusOK, meta.StatusCode)
+	assert.Equal(t, 1, meta.Attempts)
+	assert.Equal(t, "req-123", meta.RequestID)
+	assert.Equal(t, 1.25, meta.ServerTime)
+	assert.Equal(t, "10", meta.Headers.Get(CreditsHeader))
+	assert.Greater(t, meta.Duration, time.Duration(0))
+}
+
+func TestCardWithMeta_APIError(t *testing.T) {
+	mockServer, client := setupMockCardServer(`{"message": "Not found"}`, map[string]string{}, http.StatusNotFound)
+	defer mockServer.Close()
+
+	card, meta, err := client.CardWithMeta(context.Background(), CardParams{URL: "https://example.com"})
+	assert.Error(t, err)
+	assert.Nil(t, card)
+	assert.Equal(t, "card", meta.Endpoint)
+	assert.Equal(t, http.StatusNotFound, meta.StatusCode)
+	assert.Equal(t, 1, meta.Attempts)
+}
+
+func TestCardWithMeta_ValidationError(t *testing.T) {
+	mockServer, client := setupMockCardServer(`{}`, map[string]string{}, http.StatusOK)
+	defer mockServer.Close()
+
+	_, meta, err := client.CardWithMeta(context.Background(), CardParams{})
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, 0, meta.Attempts)
+}
+
+func TestSerpWithMeta_CountsAttempts(t *testing.T) {
+	var calls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"message": "busy"}`))
+			return
+		}
+		w.Header().Set(CreditsHeader, "25")
+		_, _ = w.Write([]byte(`{"organic_results": []}`))
+	}))
+	defer mockServer.Close()
+
+	client, err := NewClient("test_api_key",
+		WithBaseURL(mockServer.URL),
+		WithRetryPolicy(&BackoffPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}),
+	)
+	require.NoError(t, err)
+
+	body, meta, err := client.SerpWithMeta(context.Background(), SerpParams{Search: "golang"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"organic_results": []}`, string(body))
+	assert.Equal(t, 2, meta.Attempts)
+	assert.Equal(t, 25, meta.Credits)
+	assert.Equal(t, http.StatusOK, meta.StatusCode)
+}
+
+func TestScrapeWithMeta(t *testing.T) {
+	mockServer, client := setupMockScrapeServer(`{"success": true, "html": "<p>hi</p>"}`, map[string]string{
+		CreditsHeader:    "5",
+		"Ujb-Request-Id": "abc",
+	}, "application/json", http.StatusOK)
+	defer mockServer.Close()
+
+	res, meta, err := client.ScrapeWithMeta(context.Background(), ScrapeParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "<p>hi</p>", res.HTML)
+	assert.Equal(t, "scrape", meta.Endpoint)
+	assert.Equal(t, 5, meta.Credits)
+	assert.Equal(t, "abc", meta.RequestID)
+}
@@ -0,0 +1,77 @@
+package ujeebu
+
+import (
+	"context"
+	"fmt"
+)
+
+// Call is an API call passing through the client middlewares
+type Call struct {
+	// Endpoint is the API endpoint name (extract, scrape, card, serp, account)
+	Endpoint string
+	// Params are the ExtractParams, ScrapeParams, CardParams or SerpParams of the call, nil for account.
+	// Middlewares may replace them with a value of the same type.
+	Params any
+	// APIKey overrides the client API key for this call when set
+	APIKey string
+}
+
+// Handler executes a Call. The result is the *ExtractResponse, *RawScrapeResponse,
+// *CardResponse, []byte (serp) or *AccountResponse returned by the endpoint.
+type Handler func(ctx context.Context, call *Call) (any, ResponseMeta, error)
+
+// Middleware wraps the Handler of every API call.
+// It may inspect or rewrite the call, short-circuit it, or inspect the result, error and metadata.
+type Middleware func(next Handler) Handler
+
+// WithMiddleware adds middlewares to the client. The first middleware is the outermost one.
+// Middlewares run for Extract, Scrape, Card, Serp and Account calls, including the ones
+// made by the helpers built on them (Screenshot, GoogleSearch, batches...).
+func WithMiddleware(middlewares ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+type apiKeyKey struct{}
+
+// apiKeyFromContext returns the API key set for the call by a middleware, if any
+func apiKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(apiKeyKey{}).(string)
+	return key
+}
+
+// intercept runs next through the client middlewares
+func intercept[P, R any](ctx context.Context, c *Client, endpoint string, params P, next func(context.Context, P) (R, ResponseMeta, error)) (R, ResponseMeta, error) {
+	if len(c.middlewares) == 0 {
+		return next(ctx, params)
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	handler := Handler(func(ctx context.Context, call *Call) (any, ResponseMeta, error) {
+		p, ok := call.Params.(P)
+		if !ok && call.Params != nil {
+			return nil, ResponseMeta{Endpoint: endpoint}, fmt.Errorf("ujeebu: middleware changed %s params to %T", endpoint, call.Params)
+		}
+		if call.APIKey != "" {
+			ctx = context.WithValue(ctx, apiKeyKey{}, call.APIKey)
+		}
+		return next(ctx, p)
+	})
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		handler = c.middlewares[i](handler)
+	}
+
+	var zero R
+	res, meta, err := handler(ctx, &Call{Endpoint: endpoint, Params: params})
+	if res == nil {
+		return zero, meta, err
+	}
+	r, ok := res.(R)
+	if !ok {
+		return zero, meta, fmt.Errorf("ujeebu: middleware returned %T for %s, expected %T", res, endpoint, zero)
+	}
+	return r, meta, err
+}
@@ -0,0 +1,148 @@
+package ujeebu
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupMockMiddlewareServer echoes the url parameter and the API key of each request
+func setupMockMiddlewareServer(t *testing.T, opts ...ClientOption) (*Client, *[]string) {
+	var keys []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("ApiKey"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(CreditsHeader, "5")
+		switch r.URL.Path {
+		case "/card":
+			_ = json.NewEncoder(w).Encode(CardResponse{URL: r.URL.Query().Get("url")})
+		case "/account":
+			_ = json.NewEncoder(w).Encode(AccountResponse{Balance: 100})
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "screenshot": "c2hvdA=="})
+		}
+	}))
+	t.Cleanup(mockServer.Close)
+
+	client, err := NewClient("test_api_key", append([]ClientOption{WithBaseURL(mockServer.URL)}, opts...)...)
+	require.NoError(t, err)
+	return client, &keys
+}
+
+func TestMiddleware_Order(t *testing.T) {
+	var events []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, call *Call) (any, ResponseMeta, error) {
+				events = append(events, name+">"+call.Endpoint)
+				res, meta, err := next(ctx, call)
+				events = append(events, name+"<"+call.Endpoint)
+				return res, meta, err
+			}
+		}
+	}
+	client, _ := setupMockMiddlewareServer(t, WithMiddleware(trace("a"), trace("b")))
+
+	_, err := client.Account()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a>account", "b>account", "b<account", "a<account"}, events)
+}
+
+func TestMiddleware_SeesParamsAndResults(t *testing.T) {
+	var calls []Call
+	var metas []ResponseMeta
+	var results []any
+	observe := func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (any, ResponseMeta, error) {
+			calls = append(calls, *call)
+			res, meta, err := next(ctx, call)
+			results = append(results, res)
+			metas = append(metas, meta)
+			return res, meta, err
+		}
+	}
+	client, _ := setupMockMiddlewareServer(t, WithMiddleware(observe))
+
+	shot, credits, err := client.Screenshot(ScrapeParams{URL: "https://example.com"}, true, "")
+	require.NoError(t, err)
+	assert.Equal(t, "c2hvdA==", shot)
+	assert.Equal(t, 5, credits)
+
+	_, err = client.Account()
+	require.NoError(t, err)
+
+	require.Len(t, calls, 2)
+	params := calls[0].Params.(ScrapeParams)
+	assert.Equal(t, "scrape", calls[0].Endpoint)
+	assert.Equal(t, "screenshot", params.ResponseType)
+	assert.IsType(t, &RawScrapeResponse{}, results[0])
+	assert.Equal(t, 5, metas[0].Credits)
+	assert.Nil(t, calls[1].Params)
+	assert.IsType(t, &AccountResponse{}, results[1])
+}
+
+func TestMiddleware_RewriteAndAPIKey(t *testing.T) {
+	rewrite := func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (any, ResponseMeta, error) {
+			if p, ok := call.Params.(CardParams); ok {
+				p.URL = "https://rewritten.com"
+				call.Params = p
+				call.APIKey = "rotated_key"
+			}
+			return next(ctx, call)
+		}
+	}
+	client, keys := setupMockMiddlewareServer(t, WithMiddleware(rewrite))
+
+	card, _, err := client.Card(CardParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://rewritten.com", card.URL)
+
+	_, err = client.Account()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rotated_key", "test_api_key"}, *keys)
+}
+
+func TestMiddleware_ShortCircuit(t *testing.T) {
+	cached := func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (any, ResponseMeta, error) {
+			if call.Endpoint == "card" {
+				return &CardResponse{Title: "From middleware"}, ResponseMeta{Endpoint: call.Endpoint, Cached: true}, nil
+			}
+			return next(ctx, call)
+		}
+	}
+	client, keys := setupMockMiddlewareServer(t, WithMiddleware(cached))
+
+	card, meta, err := client.CardWithMeta(context.Background(), CardParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "From middleware", card.Title)
+	assert.True(t, meta.Cached)
+	assert.Empty(t, *keys)
+}
+
+func TestMiddleware_TypeErrors(t *testing.T) {
+	badParams := func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (any, ResponseMeta, error) {
+			call.Params = "not params"
+			return next(ctx, call)
+		}
+	}
+	client, _ := setupMockMiddlewareServer(t, WithMiddleware(badParams))
+	_, _, err := client.Card(CardParams{URL: "https://example.com"})
+	assert.ErrorContains(t, err, "middleware changed card params to string")
+
+	badResult := func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (any, ResponseMeta, error) {
+			return "not a result", ResponseMeta{}, nil
+		}
+	}
+	client, _ = setupMockMiddlewareServer(t, WithMiddleware(badResult))
+	_, _, err = client.Card(CardParams{URL: "https://example.com"})
+	assert.ErrorContains(t, err, "middleware returned string for card")
+}
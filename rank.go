@@ -0,0 +1,272 @@
+package ujeebu
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultRankDepth is the number of organic results scanned when RankOptions.Depth is not set
+const DefaultRankDepth = 50
+
+// SERP features reported by RankTracker
+const (
+	FeatureKnowledgeGraph   = "knowledge_graph"
+	FeatureTopStories       = "top_stories"
+	FeatureVideos           = "videos"
+	FeatureRelatedQuestions = "related_questions"
+	FeatureAds              = "ads"
+	FeatureFeaturedSnippet  = "featured_snippet"
+	FeaturePeopleAlsoAsk    = "people_also_ask"
+	FeatureLocalPack        = "local_pack"
+)
+
+// SerpLocale is a Location/Lang/Device combination to track rankings for
+type SerpLocale struct {
+	Location string `json:"location,omitempty"`
+	Lang     string `json:"lang,omitempty"`
+	Device   string `json:"device,omitempty"`
+}
+
+// RankOptions configures a RankTracker
+type RankOptions struct {
+	// Keywords are the search queries to track
+	Keywords []string
+	// Domains are the domains to find in the results; subdomains match too
+	Domains []string
+	// Locales are the combinations to search for each keyword (a single default locale if empty)
+	Locales []SerpLocale
+	// Depth is the number of organic results scanned per search (DefaultRankDepth if zero)
+	Depth int
+	// ResultsPerPage sets SerpParams.ResultsCount
+	ResultsPerPage int
+	// Concurrency is the number of searches run in parallel (DefaultBatchConcurrency if zero)
+	Concurrency int
+}
+
+// RankTracker finds where domains rank on Google for a set of keywords
+type RankTracker struct {
+	client *Client
+	opts   RankOptions
+}
+
+// DomainRank is the best position of a domain for a search
+type DomainRank struct {
+	Domain string `json:"domain"`
+	// Position is the 1-based organic position, 0 if not found within the depth
+	Position int `json:"position"`
+	// URL is the result that matched the domain
+	URL string `json:"url,omitempty"`
+}
+
+// RankResult holds the rankings of a keyword for one locale
+type RankResult struct {
+	Keyword string `json:"keyword"`
+	SerpLocale
+	Ranks []DomainRank `json:"ranks"`
+	// Features lists the SERP features present on the first page
+	Features []string `json:"features"`
+	// Scanned is the number of organic results scanned
+	Scanned int `json:"scanned"`
+	Credits int `json:"credits"`
+	// Error is the error message if the search failed
+	Error string `json:"error,omitempty"`
+}
+
+// RankReport holds the results of a RankTracker run
+type RankReport struct {
+	CheckedAt time.Time    `json:"checked_at"`
+	Results   []RankResult `json:"results"`
+	Credits   int          `json:"credits"`
+}
+
+// RankChange describes the movement of a domain between two reports
+type RankChange struct {
+	Keyword string `json:"keyword"`
+	SerpLocale
+	Domain   string `json:"domain"`
+	Previous int    `json:"previous"`
+	Current  int    `json:"current"`
+}
+
+// NewRankTracker returns a RankTracker using the client
+func (c *Client) NewRankTracker(opts RankOptions) *RankTracker {
+	return &RankTracker{client: c, opts: opts}
+}
+
+// Track runs a search for every keyword and locale and reports the position of each domain.
+// Failed searches are reported in RankResult.Error without stopping the run. If ctx is done
+// before every search has run, the partial report is returned with ctx.Err().
+func (t *RankTracker) Track(ctx context.Context) (*RankReport, error) {
+	if len(t.opts.Keywords) == 0 {
+		return nil, &ValidationError{Field: "Keywords", Message: "at least one keyword is required"}
+	}
+	if len(t.opts.Domains) == 0 {
+		return nil, &ValidationError{Field: "Domains", Message: "at least one domain is required"}
+	}
+
+	locales := t.opts.Locales
+	if len(locales) == 0 {
+		locales = []SerpLocale{{}}
+	}
+
+	var queries []RankResult
+	for _, keyword := range t.opts.Keywords {
+		for _, locale := range locales {
+			queries = append(queries, RankResult{Keyword: keyword, SerpLocale: locale})
+		}
+	}
+
+	report := &RankReport{CheckedAt: time.Now().UTC()}
+	batch := RunBatch(ctx, queries, BatchOptions{Concurrency: t.opts.Concurrency}, func(ctx context.Context, q RankResult) (RankResult, ResponseMeta, error) {
+		res := t.track(ctx, q)
+		return res, ResponseMeta{Credits: res.Credits}, nil
+	})
+	for _, res := range batch.Results {
+		result := res.Value
+		if res.Err != nil {
+			result = queries[res.Index]
+			result.Error = res.Err.Error()
+		}
+		report.Results = append(report.Results, result)
+		report.Credits += result.Credits
+	}
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// track pages through the results of a single query
+func (t *RankTracker) track(ctx context.Context, res RankResult) RankResult {
+	depth := t.opts.Depth
+	if depth <= 0 {
+		depth = DefaultRankDepth
+	}
+
+	res.Ranks = make([]DomainRank, len(t.opts.Domains))
+	for i, domain := range t.opts.Domains {
+		res.Ranks[i].Domain = domain
+	}
+	res.Features = []string{}
+
+	it, err := t.client.NewSerpIterator(SerpParams{
+		Search:       res.Keyword,
+		Location:     res.Location,
+		Lang:         res.Lang,
+		Device:       res.Device,
+		ResultsCount: t.opts.ResultsPerPage,
+	}, SerpIteratorOptions{MaxResults: depth})
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	found := 0
+	first := true
+	for page, err := range it.Pages(ctx) {
+		if err != nil {
+			res.Error = err.Error()
+			break
+		}
+		if first {
+			res.Features = serpFeatures(page)
+			first = false
+		}
+		for _, result := range page.OrganicResults {
+			res.Scanned++
+			for i := range res.Ranks {
+				if res.Ranks[i].Position == 0 && matchesDomain(result.Link, res.Ranks[i].Domain) {
+					res.Ranks[i].Position = res.Scanned
+					res.Ranks[i].URL = result.Link
+					found++
+				}
+			}
+		}
+		// Stop paging once every domain has been found
+		if found == len(res.Ranks) {
+			break
+		}
+	}
+	res.Credits = it.Credits()
+	return res
+}
+
+// serpFeatures lists the SERP features present on a result page
+func serpFeatures(page GoogleSearchResult) []string {
+	features := []string{}
+	if page.KnowledgeGraph.Title != "" || page.KnowledgeGraph.Type != "" || len(page.KnowledgeGraph.Attributes) > 0 {
+		features = append(features, FeatureKnowledgeGraph)
+	}
+	if len(page.TopStories) > 0 {
+		features = append(features, FeatureTopStories)
+	}
+	if len(page.Videos) > 0 {
+		features = append(features, FeatureVideos)
+	}
+	if len(page.RelatedQuestions) > 0 {
+		features = append(features, FeatureRelatedQuestions)
+	}
+	if len(page.Ads) > 0 {
+		features = append(features, FeatureAds)
+	}
+	if page.FeaturedSnippet != nil {
+		features = append(features, FeatureFeaturedSnippet)
+	}
+	if len(page.PeopleAlsoAsk) > 0 {
+		features = append(features, FeaturePeopleAlsoAsk)
+	}
+	if len(page.LocalResults) > 0 {
+		features = append(features, FeatureLocalPack)
+	}
+	return features
+}
+
+// matchesDomain reports whether link is on domain or one of its subdomains
+func matchesDomain(link, domain string) bool {
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	domain = strings.TrimPrefix(strings.ToLower(domain), "www.")
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// Diff compares the report with a previous one and returns the positions that changed
+func (r *RankReport) Diff(previous *RankReport) []RankChange {
+	type key struct {
+		keyword string
+		locale  SerpLocale
+		domain  string
+	}
+	before := map[key]int{}
+	if previous != nil {
+		for _, res := range previous.Results {
+			for _, rank := range res.Ranks {
+				before[key{res.Keyword, res.SerpLocale, rank.Domain}] = rank.Position
+			}
+		}
+	}
+
+	var changes []RankChange
+	for _, res := range r.Results {
+		if res.Error != "" {
+			continue
+		}
+		for _, rank := range res.Ranks {
+			prev := before[key{res.Keyword, res.SerpLocale, rank.Domain}]
+			if prev != rank.Position {
+				changes = append(changes, RankChange{
+					Keyword:    res.Keyword,
+					SerpLocale: res.SerpLocale,
+					Domain:     rank.Domain,
+					Previous:   prev,
+					Current:    rank.Position,
+				})
+			}
+		}
+	}
+	return changes
+}
@@ -0,0 +1,156 @@
+package ujeebu
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankTracker_Track(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		page, _ := strconv.Atoi(q.Get("page"))
+
+		body := map[string]any{}
+		switch {
+		case q.Get("search") == "broken":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message": "bad query"}`))
+			return
+		case page == 1:
+			body["organic_results"] = []OrganicResult{
+				{Link: "https://www.other.com/a"},
+				{Link: "https://blog.example.com/post"},
+			}
+			body["top_stories"] = []TopStory{{Link: "https://news.com"}}
+			body["knowledge_graph"] = map[string]string{"title": "Example"}
+		case page == 2 && q.Get("location") == "us":
+			body["organic_results"] = []OrganicResult{{Link: "https://target.org/"}}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(CreditsHeader, "10")
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer mockServer.Close()
+
+	client, err := NewClient("test_api_key", WithBaseURL(mockServer.URL))
+	require.NoError(t, err)
+
+	tracker := client.NewRankTracker(RankOptions{
+		Keywords: []string{"widgets", "broken"},
+		Domains:  []string{"example.com", "target.org"},
+		Locales:  []SerpLocale{{Location: "us", Lang: "en"}, {Location: "fr", Lang: "fr"}},
+		Depth:    10,
+	})
+	report, err := tracker.Track(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.Results, 4)
+
+	us := report.Results[0]
+	assert.Equal(t, "widgets", us.Keyword)
+	assert.Equal(t, "us", us.Location)
+	assert.Equal(t, []DomainRank{
+		{Domain: "example.com", Position: 2, URL: "https://blog.example.com/post"},
+		{Domain: "target.org", Position: 3, URL: "https://target.org/"},
+	}, us.Ranks)
+	assert.Equal(t, []string{FeatureKnowledgeGraph, FeatureTopStories}, us.Features)
+	assert.Equal(t, 20, us.Credits, "stops paging once every domain is found")
+
+	fr := report.Results[1]
+	assert.Equal(t, 0, fr.Ranks[1].Position)
+	assert.Equal(t, 2, fr.Scanned)
+
+	broken := report.Results[2]
+	assert.Equal(t, "broken", broken.Keyword)
+	assert.Contains(t, broken.Error, "bad query")
+
+	total := 0
+	for _, res := range report.Results {
+		total += res.Credits
+	}
+	assert.Equal(t, total, report.Credits)
+
+	// Reports round-trip through JSON for time-series storage
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	var decoded RankReport
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, report.Results, decoded.Results)
+	assert.True(t, report.CheckedAt.Equal(decoded.CheckedAt))
+}
+
+func TestRankTracker_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer mockServer.Close()
+
+	client, err := NewClient("test_api_key", WithBaseURL(mockServer.URL))
+	require.NoError(t, err)
+
+	tracker := client.NewRankTracker(RankOptions{
+		Keywords:    []string{"widgets", "gadgets", "gizmos"},
+		Domains:     []string{"example.com"},
+		Locales:     []SerpLocale{{Location: "us"}},
+		Concurrency: 1,
+	})
+	report, err := tracker.Track(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Len(t, report.Results, 3)
+	for i, keyword := range []string{"widgets", "gadgets", "gizmos"} {
+		assert.Equal(t, keyword, report.Results[i].Keyword)
+		assert.Equal(t, "us", report.Results[i].Location)
+		assert.NotEmpty(t, report.Results[i].Error)
+	}
+	assert.Empty(t, report.Diff(nil))
+}
+
+func TestRankTracker_Validation(t *testing.T) {
+	client := &Client{}
+	_, err := client.NewRankTracker(RankOptions{Domains: []string{"example.com"}}).Track(context.Background())
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "Keywords", validationErr.Field)
+
+	_, err = client.NewRankTracker(RankOptions{Keywords: []string{"widgets"}}).Track(context.Background())
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "Domains", validationErr.Field)
+}
+
+func TestMatchesDomain(t *testing.T) {
+	assert.True(t, matchesDomain("https://example.com/a", "example.com"))
+	assert.True(t, matchesDomain("https://www.example.com/a", "example.com"))
+	assert.True(t, matchesDomain("https://shop.example.com", "www.example.com"))
+	assert.False(t, matchesDomain("https://notexample.com", "example.com"))
+	assert.False(t, matchesDomain("not a url", "example.com"))
+}
+
+func TestRankReport_Diff(t *testing.T) {
+	locale := SerpLocale{Location: "us"}
+	previous := &RankReport{Results: []RankResult{
+		{Keyword: "widgets", SerpLocale: locale, Ranks: []DomainRank{{Domain: "a.com", Position: 3}, {Domain: "b.com", Position: 5}}},
+	}}
+	current := &RankReport{Results: []RankResult{
+		{Keyword: "widgets", SerpLocale: locale, Ranks: []DomainRank{{Domain: "a.com", Position: 1}, {Domain: "b.com", Position: 5}}},
+		{Keyword: "gadgets", SerpLocale: locale, Ranks: []DomainRank{{Domain: "a.com", Position: 7}}},
+	}}
+
+	changes := current.Diff(previous)
+	assert.Equal(t, []RankChange{
+		{Keyword: "widgets", SerpLocale: locale, Domain: "a.com", Previous: 3, Current: 1},
+		{Keyword: "gadgets", SerpLocale: locale, Domain: "a.com", Previous: 0, Current: 7},
+	}, changes)
+	assert.Len(t, current.Diff(nil), 3)
+}
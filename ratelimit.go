@@ -0,0 +1,279 @@
+package ujeebu
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultThrottleRefresh is how often WithAutoThrottle re-reads the account limits
+const DefaultThrottleRefresh = 5 * time.Minute
+
+// WithRateLimit limits the client to rps requests per second and at most
+// maxConcurrent requests in flight across Extract, Scrape, Card and Serp.
+// A zero or negative value disables the corresponding limit.
+func WithRateLimit(rps float64, maxConcurrent int) ClientOption {
+	return func(c *Client) {
+		l := c.ensureLimiter()
+		l.defaultRPS = rps
+		l.defaultConcurrency = maxConcurrent
+		l.apply(rps, maxConcurrent)
+	}
+}
+
+// WithAutoThrottle configures the rate limiter from the Account API.
+// The limits (RequestPerSecond and ConcurrentRequests) are fetched on the first
+// request and refreshed every refreshInterval (DefaultThrottleRefresh if zero).
+// Limits set with WithRateLimit are used until the account has been read and
+// whenever the account does not report a limit.
+func WithAutoThrottle(refreshInterval time.Duration) ClientOption {
+	return func(c *Client) {
+		if refreshInterval <= 0 {
+			refreshInterval = DefaultThrottleRefresh
+		}
+		l := c.ensureLimiter()
+		l.interval = refreshInterval
+		l.fetch = c.AccountWithContext
+	}
+}
+
+func (c *Client) ensureLimiter() *limiter {
+	if c.limiter == nil {
+		c.limiter = newLimiter()
+	}
+	return c.limiter
+}
+
+// limiter combines a token bucket and a concurrency semaphore
+type limiter struct {
+	bucket *tokenBucket
+	sem    *semaphore
+
+	defaultRPS         float64
+	defaultConcurrency int
+
+	// Auto throttle state
+	fetch       func(ctx context.Context) (*AccountResponse, error)
+	logger      Logger
+	interval    time.Duration
+	mu          sync.Mutex
+	ready       chan struct{}
+	started     bool
+	refreshing  bool
+	lastRefresh time.Time
+}
+
+func newLimiter() *limiter {
+	return &limiter{
+		bucket: &tokenBucket{},
+		sem:    &semaphore{},
+		ready:  make(chan struct{}),
+	}
+}
+
+// apply sets the current limits
+func (l *limiter) apply(rps float64, maxConcurrent int) {
+	l.bucket.setRate(rps)
+	l.sem.setLimit(maxConcurrent)
+}
+
+// acquire blocks until the request may be sent or ctx is done.
+// The returned function must be called once the request has completed.
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	if err := l.maybeRefresh(ctx); err != nil {
+		return nil, err
+	}
+	if err := l.sem.acquire(ctx); err != nil {
+		return nil, err
+	}
+	if err := l.bucket.wait(ctx); err != nil {
+		l.sem.release()
+		return nil, err
+	}
+	return l.sem.release, nil
+}
+
+// maybeRefresh reads the account limits on first use and whenever they are stale.
+// The first read blocks all callers until it completes or their context is done;
+// reads are detached from the caller's context so that a cancelled call cannot
+// leave the default limits in place for a whole interval.
+func (l *limiter) maybeRefresh(ctx context.Context) error {
+	if l.fetch == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	first := !l.started
+	stale := first || !l.refreshing && !l.lastRefresh.IsZero() && time.Since(l.lastRefresh) >= l.interval
+	if stale {
+		l.started = true
+		l.refreshing = true
+	}
+	l.mu.Unlock()
+
+	if stale {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+			defer cancel()
+			l.refresh(ctx)
+			if first {
+				close(l.ready)
+			}
+		}()
+	}
+
+	select {
+	case <-l.ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *limiter) refresh(ctx context.Context) {
+	account, err := l.fetch(ctx)
+
+	l.mu.Lock()
+	l.lastRefresh = time.Now()
+	l.refreshing = false
+	l.mu.Unlock()
+
+	if err != nil {
+		if l.logger != nil {
+			l.logger.Printf("ujeebu: failed to refresh rate limits from account: %v", err)
+		}
+		return
+	}
+
+	rps, concurrency := l.defaultRPS, l.defaultConcurrency
+	if account.RequestPerSecond > 0 {
+		rps = float64(account.RequestPerSecond)
+	}
+	if account.ConcurrentRequests > 0 {
+		concurrency = account.ConcurrentRequests
+	}
+	l.apply(rps, concurrency)
+}
+
+// tokenBucket spaces requests evenly at rate requests per second
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) setRate(rate float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.rate != rate {
+		b.rate = rate
+		b.tokens = 1
+		b.last = time.Now()
+	}
+}
+
+// wait reserves a token and sleeps until it becomes available.
+// The reservation is returned to the bucket if ctx is done first.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	b.mu.Lock()
+	if b.rate <= 0 {
+		b.mu.Unlock()
+		return nil
+	}
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > 1 {
+		b.tokens = 1
+	}
+	b.last = now
+	b.tokens--
+
+	if b.tokens >= 0 {
+		b.mu.Unlock()
+		return nil
+	}
+	delay := time.Duration(-b.tokens / b.rate * float64(time.Second))
+
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(delay)) {
+		b.tokens++
+		b.mu.Unlock()
+		return context.DeadlineExceeded
+	}
+	b.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// semaphore is a resizable counting semaphore honoring context cancellation
+type semaphore struct {
+	mu      sync.Mutex
+	limit   int
+	inUse   int
+	waiters []chan struct{}
+}
+
+func (s *semaphore) setLimit(limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit = limit
+	s.grant()
+}
+
+func (s *semaphore) acquire(ctx context.Context) error {
+	s.mu.Lock()
+	if s.limit <= 0 || (s.inUse < s.limit && len(s.waiters) == 0) {
+		s.inUse++
+		s.mu.Unlock()
+		return nil
+	}
+	ch := make(chan struct{})
+	s.waiters = append(s.waiters, ch)
+	s.mu.Unlock()
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, w := range s.waiters {
+			if w == ch {
+				s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
+				return ctx.Err()
+			}
+		}
+		// The slot was granted concurrently; hand it to the next waiter
+		s.inUse--
+		s.grant()
+		return ctx.Err()
+	}
+}
+
+func (s *semaphore) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inUse--
+	s.grant()
+}
+
+// grant wakes waiters while slots are available. Must be called with s.mu held.
+func (s *semaphore) grant() {
+	for len(s.waiters) > 0 && (s.limit <= 0 || s.inUse < s.limit) {
+		ch := s.waiters[0]
+		s.waiters = s.waiters[1:]
+		s.inUse++
+		close(ch)
+	}
+}
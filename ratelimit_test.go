@@ -0,0 +1,188 @@
+package ujeebu
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket_SpacesRequests(t *testing.T) {
+	b := &tokenBucket{}
+	b.setRate(20) // one token every 50ms
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		require.NoError(t, b.wait(context.Background()))
+	}
+	elapsed := time.Since(start)
+
+	// First token is immediate, the next three are spaced 50ms apart
+	assert.GreaterOrEqual(t, elapsed, 140*time.Millisecond)
+	assert.Less(t, elapsed, time.Second)
+}
+
+func TestTokenBucket_RespectsDeadline(t *testing.T) {
+	b := &tokenBucket{}
+	b.setRate(1)
+	require.NoError(t, b.wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := b.wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "should fail fast when the deadline cannot be met")
+}
+
+func TestSemaphore_LimitsConcurrency(t *testing.T) {
+	s := &semaphore{}
+	s.setLimit(2)
+
+	var inFlight, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, s.acquire(context.Background()))
+			n := atomic.AddInt32(&inFlight, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+			s.release()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), peak)
+	assert.Equal(t, 0, s.inUse)
+}
+
+func TestSemaphore_ContextCancel(t *testing.T) {
+	s := &semaphore{}
+	s.setLimit(1)
+	require.NoError(t, s.acquire(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, s.acquire(ctx), context.DeadlineExceeded)
+	assert.Empty(t, s.waiters)
+
+	s.release()
+	assert.NoError(t, s.acquire(context.Background()))
+}
+
+func TestWithRateLimit_ContextDeadline(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"title": "ok"}`))
+	}))
+	defer mockServer.Close()
+
+	client, err := NewClient("test_api_key", WithBaseURL(mockServer.URL), WithRateLimit(1, 1))
+	require.NoError(t, err)
+
+	_, _, err = client.Card(CardParams{URL: "https://example.com"})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, _, err = client.CardWithContext(ctx, CardParams{URL: "https://example.com"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWithAutoThrottle_ConfiguresFromAccount(t *testing.T) {
+	var accountCalls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/account" {
+			atomic.AddInt32(&accountCalls, 1)
+			_, _ = w.Write([]byte(`{"requests_per_second": 5, "concurrent_requests": 3}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"title": "ok"}`))
+	}))
+	defer mockServer.Close()
+
+	client, err := NewClient("test_api_key", WithBaseURL(mockServer.URL), WithAutoThrottle(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&accountCalls), "account should be read lazily")
+
+	for i := 0; i < 3; i++ {
+		_, _, err = client.Card(CardParams{URL: "https://example.com"})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&accountCalls))
+	assert.Equal(t, float64(5), client.limiter.bucket.rate)
+	assert.Equal(t, 3, client.limiter.sem.limit)
+}
+
+func TestWithAutoThrottle_FallsBackOnAccountError(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/account" {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"message": "boom"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"title": "ok"}`))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		apiKey: "test_api_key",
+		client: resty.New().SetBaseURL(mockServer.URL).SetTimeout(5 * time.Second),
+	}
+	WithRateLimit(100, 4)(client)
+	WithAutoThrottle(time.Hour)(client)
+
+	_, _, err := client.Card(CardParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, float64(100), client.limiter.bucket.rate)
+	assert.Equal(t, 4, client.limiter.sem.limit)
+}
+
+func TestWithAutoThrottle_FirstCallCancelled(t *testing.T) {
+	var accountCalls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/account" {
+			atomic.AddInt32(&accountCalls, 1)
+			time.Sleep(100 * time.Millisecond)
+			_, _ = w.Write([]byte(`{"requests_per_second": 5, "concurrent_requests": 3}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"title": "ok"}`))
+	}))
+	defer mockServer.Close()
+
+	client, err := NewClient("test_api_key", WithBaseURL(mockServer.URL), WithAutoThrottle(time.Hour))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, _, err = client.CardWithContext(ctx, CardParams{URL: "https://example.com"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The account read outlives the cancelled call and configures the limits
+	_, _, err = client.Card(CardParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&accountCalls))
+	assert.Equal(t, float64(5), client.limiter.bucket.rate)
+	assert.Equal(t, 3, client.limiter.sem.limit)
+}
@@ -0,0 +1,181 @@
+package ujeebu
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy decides whether a failed attempt should be retried.
+// Retry returns the delay to wait before the next attempt and whether to retry at all.
+// Policies must not retry non-idempotent attempts unless explicitly configured to.
+type RetryPolicy interface {
+	Retry(attempt RetryAttempt) (time.Duration, bool)
+}
+
+// RetryAttempt describes a completed attempt of an API call
+type RetryAttempt struct {
+	// Endpoint is the API endpoint name (extract, scrape, card, serp, account)
+	Endpoint string
+	// Attempt is the 1-based attempt number
+	Attempt int
+	// Err is the error of the attempt, either an *APIError or a *NetworkError
+	Err error
+	// StatusCode is the HTTP status code, 0 if no response was received
+	StatusCode int
+	// RetryAfter is the delay requested by the Retry-After header, 0 if absent
+	RetryAfter time.Duration
+	// Elapsed is the time spent on the call so far, including previous attempts
+	Elapsed time.Duration
+	// Idempotent is false for calls that may have side effects when repeated,
+	// such as a Scrape with HTTPMethod POST or PostData
+	Idempotent bool
+}
+
+// RetryEvent is passed to the retry hook after every attempt
+type RetryEvent struct {
+	RetryAttempt
+	// Retrying is true if another attempt will be made
+	Retrying bool
+	// Delay is the wait before the next attempt
+	Delay time.Duration
+}
+
+// WithRetryPolicy sets the policy used to retry failed requests
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithRetryHook registers a function called after every attempt of every API call
+func WithRetryHook(hook func(RetryEvent)) ClientOption {
+	return func(c *Client) {
+		c.retryHook = hook
+	}
+}
+
+// BackoffPolicy is the built-in RetryPolicy using jittered exponential backoff.
+// It retries network errors, 408, 429 and 5xx responses, and honors Retry-After.
+type BackoffPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first one
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, doubled after each attempt
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff between attempts; a longer Retry-After is not retried
+	MaxDelay time.Duration
+	// MaxElapsed caps the total time spent on a call, 0 means no limit
+	MaxElapsed time.Duration
+	// RetryStatusCodes overrides the status codes that are retried
+	RetryStatusCodes []int
+	// RetryNonIdempotent allows retrying calls that are not idempotent
+	RetryNonIdempotent bool
+}
+
+// NewBackoffPolicy returns a BackoffPolicy with sensible defaults
+func NewBackoffPolicy(maxAttempts int) *BackoffPolicy {
+	return &BackoffPolicy{
+		MaxAttempts: maxAttempts,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    30 * time.Second,
+	}
+}
+
+// Retry implements RetryPolicy
+func (p *BackoffPolicy) Retry(a RetryAttempt) (time.Duration, bool) {
+	if a.Attempt >= p.MaxAttempts {
+		return 0, false
+	}
+	if !a.Idempotent && !p.RetryNonIdempotent {
+		return 0, false
+	}
+	if !p.retryable(a) {
+		return 0, false
+	}
+
+	delay := a.RetryAfter
+	if delay == 0 {
+		delay = p.backoff(a.Attempt)
+	} else if p.MaxDelay > 0 && delay > p.MaxDelay {
+		return 0, false
+	}
+	if p.MaxElapsed > 0 && a.Elapsed+delay > p.MaxElapsed {
+		return 0, false
+	}
+	return delay, true
+}
+
+func (p *BackoffPolicy) retryable(a RetryAttempt) bool {
+	var netErr *NetworkError
+	if errors.As(a.Err, &netErr) {
+		return !errors.Is(netErr.Err, context.Canceled) && !errors.Is(netErr.Err, context.DeadlineExceeded)
+	}
+
+	var apiErr *APIError
+	if !errors.As(a.Err, &apiErr) {
+		return false
+	}
+	if p.RetryStatusCodes != nil {
+		for _, code := range p.RetryStatusCodes {
+			if apiErr.StatusCode == code {
+				return true
+			}
+		}
+		return false
+	}
+	return apiErr.IsRateLimited() || apiErr.IsTimeout() || apiErr.StatusCode >= http.StatusInternalServerError
+}
+
+// backoff returns a full-jitter exponential delay for the given attempt
+func (p *BackoffPolicy) backoff(attempt int) time.Duration {
+	base := p.BaseDelay
+	if base <= 0 {
+		base = 500 * time.Millisecond
+	}
+	limit := float64(base) * math.Pow(2, float64(attempt-1))
+	if p.MaxDelay > 0 && limit > float64(p.MaxDelay) {
+		limit = float64(p.MaxDelay)
+	}
+	half := limit / 2
+	return time.Duration(half + rand.Float64()*half)
+}
+
+// parseRetryAfter parses a Retry-After header in seconds or HTTP-date form
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,205 @@
+package ujeebu
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoffPolicy_Retry(t *testing.T) {
+	policy := &BackoffPolicy{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond, MaxDelay: 100 * time.Millisecond}
+
+	tests := []struct {
+		name    string
+		attempt RetryAttempt
+		retry   bool
+	}{
+		{
+			name:    "rate limited",
+			attempt: RetryAttempt{Attempt: 1, Idempotent: true, Err: &APIError{StatusCode: http.StatusTooManyRequests}},
+			retry:   true,
+		},
+		{
+			name:    "request timeout",
+			attempt: RetryAttempt{Attempt: 1, Idempotent: true, Err: &APIError{StatusCode: http.StatusRequestTimeout}},
+			retry:   true,
+		},
+		{
+			name:    "server error",
+			attempt: RetryAttempt{Attempt: 2, Idempotent: true, Err: &APIError{StatusCode: http.StatusBadGateway}},
+			retry:   true,
+		},
+		{
+			name:    "network error",
+			attempt: RetryAttempt{Attempt: 1, Idempotent: true, Err: &NetworkError{Err: errors.New("connection reset")}},
+			retry:   true,
+		},
+		{
+			name:    "context canceled",
+			attempt: RetryAttempt{Attempt: 1, Idempotent: true, Err: &NetworkError{Err: context.Canceled}},
+			retry:   false,
+		},
+		{
+			name:    "not found",
+			attempt: RetryAttempt{Attempt: 1, Idempotent: true, Err: &APIError{StatusCode: http.StatusNotFound}},
+			retry:   false,
+		},
+		{
+			name:    "max attempts reached",
+			attempt: RetryAttempt{Attempt: 3, Idempotent: true, Err: &APIError{StatusCode: http.StatusServiceUnavailable}},
+			retry:   false,
+		},
+		{
+			name:    "non-idempotent",
+			attempt: RetryAttempt{Attempt: 1, Idempotent: false, Err: &APIError{StatusCode: http.StatusServiceUnavailable}},
+			retry:   false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			delay, retry := policy.Retry(tc.attempt)
+			assert.Equal(t, tc.retry, retry)
+			if retry {
+				assert.LessOrEqual(t, delay, 100*time.Millisecond)
+				assert.Greater(t, delay, time.Duration(0))
+			}
+		})
+	}
+}
+
+func TestBackoffPolicy_RetryAfterAndMaxElapsed(t *testing.T) {
+	policy := &BackoffPolicy{MaxAttempts: 5, BaseDelay: 10 * time.Millisecond, MaxElapsed: 5 * time.Second}
+	attempt := RetryAttempt{
+		Attempt:    1,
+		Idempotent: true,
+		Err:        &APIError{StatusCode: http.StatusTooManyRequests},
+		RetryAfter: 2 * time.Second,
+	}
+
+	delay, retry := policy.Retry(attempt)
+	assert.True(t, retry)
+	assert.Equal(t, 2*time.Second, delay)
+
+	attempt.Elapsed = 4 * time.Second
+	_, retry = policy.Retry(attempt)
+	assert.False(t, retry, "retry would exceed MaxElapsed")
+}
+
+func TestBackoffPolicy_RetryAfterAboveMaxDelay(t *testing.T) {
+	policy := &BackoffPolicy{MaxAttempts: 3, MaxDelay: 30 * time.Second}
+	attempt := RetryAttempt{
+		Attempt:    1,
+		Idempotent: true,
+		Err:        &APIError{StatusCode: http.StatusTooManyRequests},
+		RetryAfter: 120 * time.Second,
+	}
+	_, retry := policy.Retry(attempt)
+	assert.False(t, retry, "retrying before Retry-After would be rejected again")
+
+	attempt.RetryAfter = 30 * time.Second
+	delay, retry := policy.Retry(attempt)
+	assert.True(t, retry)
+	assert.Equal(t, 30*time.Second, delay)
+}
+
+func TestBackoffPolicy_RetryNonIdempotent(t *testing.T) {
+	policy := &BackoffPolicy{MaxAttempts: 2, RetryNonIdempotent: true}
+	_, retry := policy.Retry(RetryAttempt{Attempt: 1, Err: &APIError{StatusCode: http.StatusServiceUnavailable}})
+	assert.True(t, retry)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, 3*time.Second, parseRetryAfter("3"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(""))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("-1"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("garbage"))
+
+	future := time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)
+	d := parseRetryAfter(future)
+	assert.Greater(t, d, 5*time.Second)
+	assert.LessOrEqual(t, d, 10*time.Second)
+}
+
+func TestWithRetryPolicy_RetriesUntilSuccess(t *testing.T) {
+	var calls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"message": "busy"}`))
+			return
+		}
+		w.Header().Set(CreditsHeader, "2")
+		_, _ = w.Write([]byte(`{"title": "ok"}`))
+	}))
+	defer mockServer.Close()
+
+	var events []RetryEvent
+	client, err := NewClient("test_api_key",
+		WithBaseURL(mockServer.URL),
+		WithRetryPolicy(&BackoffPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}),
+		WithRetryHook(func(e RetryEvent) { events = append(events, e) }),
+	)
+	require.NoError(t, err)
+
+	card, credits, err := client.Card(CardParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "ok", card.Title)
+	assert.Equal(t, 2, credits)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	require.Len(t, events, 3)
+	assert.True(t, events[0].Retrying)
+	assert.Equal(t, http.StatusServiceUnavailable, events[0].StatusCode)
+	assert.Equal(t, "card", events[0].Endpoint)
+	assert.False(t, events[2].Retrying)
+	assert.NoError(t, events[2].Err)
+	assert.Equal(t, 3, events[2].Attempt)
+}
+
+func TestWithRetryPolicy_ReturnsLastAPIError(t *testing.T) {
+	var calls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"message": "slow down"}`))
+	}))
+	defer mockServer.Close()
+
+	client, err := NewClient("test_api_key", WithBaseURL(mockServer.URL), WithRetry(2, time.Millisecond, 5*time.Millisecond))
+	require.NoError(t, err)
+
+	_, _, err = client.Serp(SerpParams{Search: "golang"})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.True(t, apiErr.IsRateLimited())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestWithRetryPolicy_SkipsNonIdempotentScrape(t *testing.T) {
+	var calls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte(`{"message": "upstream failed"}`))
+	}))
+	defer mockServer.Close()
+
+	client, err := NewClient("test_api_key", WithBaseURL(mockServer.URL), WithRetry(3, time.Millisecond, time.Millisecond))
+	require.NoError(t, err)
+
+	_, _, err = client.Scrape(ScrapeParams{URL: "https://example.com/form", HTTPMethod: "post", PostData: "a=1"})
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
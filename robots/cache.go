@@ -0,0 +1,197 @@
+package robots
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ujeebu/ujeebu-go"
+)
+
+// DefaultTTL is how long robots.txt files are cached when Options.TTL is not set
+const DefaultTTL = 24 * time.Hour
+
+// DefaultUnreachableTTL is how long unreachable robots.txt files are cached when
+// Options.UnreachableTTL is not set
+const DefaultUnreachableTTL = 5 * time.Minute
+
+// Options configures a Cache
+type Options struct {
+	// Params are the scrape parameters of robots.txt requests (proxy, session, headers...).
+	// URL is set to the robots.txt URL of the host and files are fetched in raw mode
+	// without JS rendering, so ResponseType, JS, JSONOutput and ExtractRules are ignored.
+	Params ujeebu.ScrapeParams
+	// UserAgent is matched against the groups of the files, Params.UserAgent if empty
+	UserAgent string
+	// TTL is how long a file is cached (DefaultTTL if zero)
+	TTL time.Duration
+	// UnreachableTTL is how long a host whose file is unreachable stays disallowed
+	// (DefaultUnreachableTTL if zero)
+	UnreachableTTL time.Duration
+}
+
+// Cache fetches the robots.txt files of hosts through the Scrape API and caches them per host.
+// It is safe for concurrent use; concurrent lookups of the same host share a single request.
+type Cache struct {
+	scraper ujeebu.Scraper
+	opts    Options
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// entry is the cached robots.txt of a host
+type entry struct {
+	// ready is closed once the file is fetched
+	ready   chan struct{}
+	robots  *Robots
+	expires time.Time
+}
+
+// NewCache creates a Cache fetching files with scraper, usually a *ujeebu.Client
+func NewCache(scraper ujeebu.Scraper, opts Options) (*Cache, error) {
+	if scraper == nil {
+		return nil, &ujeebu.ValidationError{Field: "scraper", Message: "scraper is required"}
+	}
+	if opts.TTL < 0 {
+		return nil, &ujeebu.ValidationError{Field: "TTL", Message: "TTL must not be negative"}
+	}
+	if opts.UnreachableTTL < 0 {
+		return nil, &ujeebu.ValidationError{Field: "UnreachableTTL", Message: "UnreachableTTL must not be negative"}
+	}
+	if opts.TTL == 0 {
+		opts.TTL = DefaultTTL
+	}
+	if opts.UnreachableTTL == 0 {
+		opts.UnreachableTTL = DefaultUnreachableTTL
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = opts.Params.UserAgent
+	}
+	return &Cache{scraper: scraper, opts: opts, entries: map[string]*entry{}}, nil
+}
+
+// UserAgent returns the user agent matched against the groups of the files
+func (c *Cache) UserAgent() string {
+	return c.opts.UserAgent
+}
+
+// Get returns the robots.txt of the host of rawURL.
+// Files that do not exist (4xx responses) allow everything. Unreachable files (5xx
+// responses and other failures) disallow everything for Options.UnreachableTTL, as
+// RFC 9309 recommends. An error is returned only when ctx is done, and is not cached.
+// Concurrent callers wait for the request in flight; if it fails, the next caller fetches
+// the file again.
+func (c *Cache) Get(ctx context.Context, rawURL string) (*Robots, error) {
+	origin, err := originOf(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		c.mu.Lock()
+		if e, ok := c.entries[origin]; ok {
+			select {
+			case <-e.ready:
+				if time.Now().Before(e.expires) {
+					c.mu.Unlock()
+					return e.robots, nil
+				}
+			default:
+				c.mu.Unlock()
+				select {
+				case <-e.ready:
+					continue
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			}
+		}
+		e := &entry{ready: make(chan struct{})}
+		c.entries[origin] = e
+		c.mu.Unlock()
+
+		r, ttl, err := c.fetch(ctx, origin)
+		e.robots, e.expires = r, time.Now().Add(ttl)
+		if err != nil {
+			c.mu.Lock()
+			if c.entries[origin] == e {
+				delete(c.entries, origin)
+			}
+			c.mu.Unlock()
+		}
+		close(e.ready)
+		return r, err
+	}
+}
+
+// Allowed reports whether the user agent may fetch rawURL
+func (c *Cache) Allowed(ctx context.Context, rawURL string) (bool, error) {
+	r, err := c.Get(ctx, rawURL)
+	if err != nil {
+		return false, err
+	}
+	return r.Allowed(c.opts.UserAgent, rawURL), nil
+}
+
+// CrawlDelay returns the Crawl-delay for the user agent on the host of rawURL
+func (c *Cache) CrawlDelay(ctx context.Context, rawURL string) (time.Duration, error) {
+	r, err := c.Get(ctx, rawURL)
+	if err != nil {
+		return 0, err
+	}
+	return r.CrawlDelay(c.opts.UserAgent), nil
+}
+
+// fetch fetches and parses the robots.txt of origin, and returns how long to cache it
+func (c *Cache) fetch(ctx context.Context, origin string) (*Robots, time.Duration, error) {
+	params := c.opts.Params
+	params.URL = origin + "/robots.txt"
+	params.ResponseType = "raw"
+	params.JS = false
+	params.JSONOutput = false
+	params.ExtractRules = nil
+
+	res, _, err := c.scraper.ScrapeRawWithMeta(ctx, params)
+	var apiErr *ujeebu.APIError
+	switch {
+	case err != nil && ctx.Err() != nil:
+		return nil, 0, fmt.Errorf("robots: fetching %s: %w", params.URL, err)
+	case errors.As(err, &apiErr) && unavailable(apiErr.StatusCode):
+		return AllowAll(), c.opts.TTL, nil
+	case err == nil && res.StatusCode >= 200 && res.StatusCode < 300:
+		return Parse(res.Body), c.opts.TTL, nil
+	case err == nil && unavailable(res.StatusCode):
+		return AllowAll(), c.opts.TTL, nil
+	default:
+		return DisallowAll(), c.opts.UnreachableTTL, nil
+	}
+}
+
+// unavailable reports whether a status code means that the site has no robots.txt.
+// Statuses also used by the API for the account or the request itself are excluded.
+func unavailable(statusCode int) bool {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusPaymentRequired, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+	return statusCode >= 400 && statusCode < 500
+}
+
+// originOf returns the scheme and host of an absolute http or https URL
+func originOf(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if (scheme != "http" && scheme != "https") || u.Host == "" {
+		return "", &ujeebu.ValidationError{Field: "URL", Message: fmt.Sprintf("%q is not an absolute http or https URL", rawURL)}
+	}
+	return scheme + "://" + strings.ToLower(u.Host), nil
+}
@@ -0,0 +1,198 @@
+package robots
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ujeebu/ujeebu-go"
+	"github.com/ujeebu/ujeebu-go/ujeebutest"
+)
+
+// newRobotsServer serves the robots.txt files of fake hosts from the scrape endpoint
+func newRobotsServer(t *testing.T) *ujeebutest.Server {
+	files := map[string]string{
+		"https://example.com/robots.txt":  "User-agent: *\nDisallow: /private/\nCrawl-delay: 1\n\nUser-agent: MyBot\nDisallow: /\n",
+		"https://flaky.com/robots.txt":    "",
+		"https://redirect.com/robots.txt": "",
+	}
+	server := ujeebutest.NewServer(t)
+	server.Handle("scrape", func(w http.ResponseWriter, r *http.Request) {
+		u := r.URL.Query().Get("url")
+		switch body, ok := files[u]; {
+		case u == "https://flaky.com/robots.txt":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"message": "target unreachable"}`))
+		case !ok:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "page not found"}`))
+		default:
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(body))
+		}
+	})
+	return server
+}
+
+func TestCache_Get(t *testing.T) {
+	server := newRobotsServer(t)
+	cache, err := NewCache(server.Client(), Options{
+		Params: ujeebu.ScrapeParams{JS: true, ProxyType: "residential", ResponseType: "screenshot", UserAgent: "Mozilla/5.0 (compatible; SomeBot/1.0)"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Mozilla/5.0 (compatible; SomeBot/1.0)", cache.UserAgent())
+
+	allowed, err := cache.Allowed(context.Background(), "https://example.com/blog/post")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	allowed, err = cache.Allowed(context.Background(), "https://EXAMPLE.com/private/x")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	delay, err := cache.CrawlDelay(context.Background(), "https://example.com/")
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, delay)
+
+	// The file is fetched once, in raw mode, with the scrape settings
+	server.AssertCalls(t, "scrape", 1)
+	req, _ := server.LastRequest("scrape")
+	assert.Equal(t, "https://example.com/robots.txt", req.Param("url"))
+	assert.Equal(t, "raw", req.Param("response_type"))
+	assert.Equal(t, "false", req.Param("js"))
+	assert.Equal(t, "residential", req.Param("proxy_type"))
+
+	// A missing file allows everything
+	allowed, err = cache.Allowed(context.Background(), "https://nofile.com/private/x")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+}
+
+func TestCache_UserAgent(t *testing.T) {
+	server := newRobotsServer(t)
+	cache, err := NewCache(server.Client(), Options{UserAgent: "MyBot/1.0"})
+	require.NoError(t, err)
+
+	allowed, err := cache.Allowed(context.Background(), "https://example.com/blog/post")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestCache_Errors(t *testing.T) {
+	server := newRobotsServer(t)
+	cache, err := NewCache(server.Client(), Options{UnreachableTTL: 20 * time.Millisecond})
+	require.NoError(t, err)
+
+	// Unreachable files disallow everything for UnreachableTTL
+	allowed, err := cache.Allowed(context.Background(), "https://flaky.com/page")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	_, err = cache.Get(context.Background(), "https://flaky.com/other")
+	require.NoError(t, err)
+	server.AssertCalls(t, "scrape", 1)
+	time.Sleep(30 * time.Millisecond)
+	_, err = cache.Get(context.Background(), "https://flaky.com/page")
+	require.NoError(t, err)
+	server.AssertCalls(t, "scrape", 2)
+
+	// Account errors are not mistaken for a missing file
+	server.Fail("scrape", ujeebutest.Fault{StatusCode: http.StatusPaymentRequired, Message: "no credits", Times: 1})
+	allowed, err = cache.Allowed(context.Background(), "https://example.com/")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	// Cancellation is returned and not cached
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = cache.Get(ctx, "https://nofile.com/")
+	assert.ErrorIs(t, err, context.Canceled)
+	allowed, err = cache.Allowed(context.Background(), "https://nofile.com/")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	var validationErr *ujeebu.ValidationError
+	_, err = cache.Get(context.Background(), "/relative")
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = NewCache(nil, Options{})
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = NewCache(server.Client(), Options{UnreachableTTL: -time.Second})
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestCache_Expiry(t *testing.T) {
+	server := newRobotsServer(t)
+	cache, err := NewCache(server.Client(), Options{TTL: time.Millisecond})
+	require.NoError(t, err)
+
+	_, err = cache.Get(context.Background(), "https://example.com/")
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	_, err = cache.Get(context.Background(), "https://example.com/")
+	require.NoError(t, err)
+	server.AssertCalls(t, "scrape", 2)
+}
+
+func TestCache_Concurrent(t *testing.T) {
+	server := newRobotsServer(t)
+	server.SetLatency("scrape", 20*time.Millisecond)
+	cache, err := NewCache(server.Client(), Options{})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := cache.Get(context.Background(), "https://example.com/")
+			assert.NoError(t, err)
+			assert.NotNil(t, r)
+		}()
+	}
+	wg.Wait()
+	server.AssertCalls(t, "scrape", 1)
+}
+
+func TestCache_CancelledFetch(t *testing.T) {
+	started := make(chan struct{})
+	var calls int32
+	mock := &ujeebutest.MockAPI{}
+	mock.ScrapeFunc = func(ctx context.Context, params ujeebu.ScrapeParams) (*ujeebu.RawScrapeResponse, ujeebu.ResponseMeta, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+			<-ctx.Done()
+			return nil, ujeebu.ResponseMeta{}, &ujeebu.NetworkError{Err: ctx.Err()}
+		}
+		return &ujeebu.RawScrapeResponse{Body: []byte("User-agent: *\nDisallow: /private/\n"), StatusCode: http.StatusOK}, ujeebu.ResponseMeta{}, nil
+	}
+	cache, err := NewCache(mock, Options{})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := cache.Get(ctx, "https://example.com/")
+		first <- err
+	}()
+	<-started
+
+	// A caller waiting on the cancelled request fetches the file itself
+	second := make(chan *Robots, 1)
+	go func() {
+		r, err := cache.Get(context.Background(), "https://example.com/")
+		assert.NoError(t, err)
+		second <- r
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	assert.ErrorIs(t, <-first, context.Canceled)
+	r := <-second
+	require.NotNil(t, r)
+	assert.False(t, r.Allowed("", "https://example.com/private/x"))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,271 @@
+// Package robots fetches, parses and evaluates robots.txt files, and spaces the
+// requests to each host according to their Crawl-delay.
+//
+// Files are fetched through the Scrape API in raw mode, so they go through the
+// same proxies as the pages they apply to:
+//
+//	cache, err := robots.NewCache(client, robots.Options{
+//		Params: ujeebu.ScrapeParams{ProxyType: "residential", UserAgent: "MyBot/1.0"},
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	allowed, err := cache.Allowed(ctx, "https://example.com/private/page")
+package robots
+
+import (
+	"bufio"
+	"bytes"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxSize is the maximum size of a robots.txt file, the rest of larger files is ignored
+const MaxSize = 500 << 10
+
+// Rule is an Allow or Disallow line of a group
+type Rule struct {
+	// Allow is set for Allow rules
+	Allow bool
+	// Pattern is the path pattern of the rule, with * matching any sequence of characters and a trailing $ anchoring the end
+	Pattern string
+}
+
+// Group holds the rules of a set of user agents
+type Group struct {
+	// Agents are the lowercased user agent tokens of the group, * for any crawler
+	Agents []string
+	// Rules are the Allow and Disallow rules of the group, in file order
+	Rules []Rule
+	// CrawlDelay is the minimum delay between requests, zero if not set
+	CrawlDelay time.Duration
+}
+
+// Robots is a parsed robots.txt file
+type Robots struct {
+	// Groups are the groups of the file, in file order
+	Groups []*Group
+	// Sitemaps are the URLs of the Sitemap lines
+	Sitemaps []string
+
+	disallowAll bool
+}
+
+// AllowAll returns robots allowing every URL, used when a site has no robots.txt
+func AllowAll() *Robots {
+	return &Robots{}
+}
+
+// DisallowAll returns robots disallowing every URL, used when the robots.txt of a site is unreachable
+func DisallowAll() *Robots {
+	return &Robots{disallowAll: true}
+}
+
+// Parse parses a robots.txt file. Invalid lines are ignored, as are rules before the first User-agent line.
+func Parse(data []byte) *Robots {
+	if len(data) > MaxSize {
+		data = data[:MaxSize]
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	r := &Robots{}
+	var current *Group
+	// inRules is set once the current group has rules, so that the next User-agent line starts a new group
+	inRules := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 4096), MaxSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent", "useragent", "user agent":
+			if current == nil || inRules {
+				current = &Group{}
+				r.Groups = append(r.Groups, current)
+				inRules = false
+			}
+			current.Agents = append(current.Agents, agentToken(value))
+		case "allow", "disallow":
+			if current == nil {
+				continue
+			}
+			inRules = true
+			// An empty Disallow allows everything, which is the default
+			if value != "" {
+				current.Rules = append(current.Rules, Rule{Allow: key == "allow", Pattern: normalizeEncoding(value)})
+			}
+		case "crawl-delay":
+			if current == nil {
+				continue
+			}
+			inRules = true
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds >= 0 {
+				current.CrawlDelay = time.Duration(seconds * float64(time.Second))
+			}
+		case "sitemap", "site-map":
+			if u, err := url.Parse(value); err == nil && u.IsAbs() {
+				r.Sitemaps = append(r.Sitemaps, value)
+			}
+		}
+	}
+	return r
+}
+
+// agentToken returns the lowercased product token of a User-agent line value, without version
+func agentToken(value string) string {
+	value = strings.ToLower(value)
+	if i := strings.IndexAny(value, "/ "); i >= 0 {
+		value = value[:i]
+	}
+	return value
+}
+
+// Group returns the group applying to userAgent, merging groups with the same agent token.
+// The group with the longest agent token found in the lowercased userAgent is used, and the
+// * group otherwise. It returns nil when no group applies, meaning everything is allowed.
+func (r *Robots) Group(userAgent string) *Group {
+	userAgent = strings.ToLower(userAgent)
+	best := ""
+	for _, g := range r.Groups {
+		for _, agent := range g.Agents {
+			if agent != "*" && agent != "" && len(agent) > len(best) && strings.Contains(userAgent, agent) {
+				best = agent
+			}
+		}
+	}
+	if best == "" {
+		best = "*"
+	}
+
+	var merged *Group
+	for _, g := range r.Groups {
+		for _, agent := range g.Agents {
+			if agent != best {
+				continue
+			}
+			if merged == nil {
+				merged = &Group{Agents: []string{best}}
+			}
+			merged.Rules = append(merged.Rules, g.Rules...)
+			if g.CrawlDelay > merged.CrawlDelay {
+				merged.CrawlDelay = g.CrawlDelay
+			}
+			break
+		}
+	}
+	return merged
+}
+
+// Allowed reports whether userAgent may fetch rawURL, an absolute URL or a path with an optional query.
+// The longest matching rule applies, Allow winning ties; URLs matching no rule are allowed.
+func (r *Robots) Allowed(userAgent, rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if path == "/robots.txt" {
+		return true
+	}
+	if r.disallowAll {
+		return false
+	}
+	g := r.Group(userAgent)
+	if g == nil {
+		return true
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	path = normalizeEncoding(path)
+
+	allowed, longest := true, -1
+	for _, rule := range g.Rules {
+		if !match(rule.Pattern, path) {
+			continue
+		}
+		if n := len(rule.Pattern); n > longest || (n == longest && rule.Allow) {
+			allowed, longest = rule.Allow, n
+		}
+	}
+	return allowed
+}
+
+// CrawlDelay returns the Crawl-delay of the group applying to userAgent, zero if not set
+func (r *Robots) CrawlDelay(userAgent string) time.Duration {
+	if g := r.Group(userAgent); g != nil {
+		return g.CrawlDelay
+	}
+	return 0
+}
+
+// match reports whether path matches a rule pattern
+func match(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	if anchored {
+		pattern = pattern[:len(pattern)-1]
+	}
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	if len(parts) == 1 {
+		return !anchored || path == pattern
+	}
+
+	rest := path[len(parts[0]):]
+	// Leftmost matching of the middle parts leaves the most room for the next ones
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(rest, part)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(part):]
+	}
+	last := parts[len(parts)-1]
+	if anchored {
+		return strings.HasSuffix(rest, last)
+	}
+	return strings.Contains(rest, last)
+}
+
+// normalizeEncoding percent-encodes the non-ASCII bytes of s and uppercases its escapes,
+// so that patterns and URL paths compare equal whatever their encoding
+func normalizeEncoding(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 0x80:
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&15])
+		case c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			b.WriteByte('%')
+			b.WriteString(strings.ToUpper(s[i+1 : i+3]))
+			i += 2
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
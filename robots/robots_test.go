@@ -0,0 +1,136 @@
+package robots
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sample = `# robots.txt for example.com
+User-agent: *
+Disallow: /private/
+Disallow: /*.pdf$
+Allow: /private/public/
+Crawl-delay: 2
+
+User-agent: MyBot/1.0
+User-agent: OtherBot
+Disallow: /
+Allow: /blog/
+
+User-agent: mybot
+Crawl-delay: 0.5
+Disallow: /blog/drafts
+
+Sitemap: https://example.com/sitemap.xml
+Sitemap: /relative.xml
+`
+
+func TestParse(t *testing.T) {
+	r := Parse([]byte(sample))
+	assert.Len(t, r.Groups, 3)
+	assert.Equal(t, []string{"*"}, r.Groups[0].Agents)
+	assert.Equal(t, []string{"mybot", "otherbot"}, r.Groups[1].Agents)
+	assert.Equal(t, 2*time.Second, r.Groups[0].CrawlDelay)
+	assert.Equal(t, []Rule{{Pattern: "/"}, {Allow: true, Pattern: "/blog/"}}, r.Groups[1].Rules)
+	assert.Equal(t, []string{"https://example.com/sitemap.xml"}, r.Sitemaps)
+}
+
+func TestParse_Lenient(t *testing.T) {
+	r := Parse([]byte("\xef\xbb\xbfDisallow: /before-any-agent\r\nuser-agent:*\r\ndisallow:/a # comment\r\nnot a rule\r\nDISALLOW: \r\nCrawl-delay: soon\r\n"))
+	assert.Len(t, r.Groups, 1)
+	assert.Equal(t, []Rule{{Pattern: "/a"}}, r.Groups[0].Rules)
+	assert.Zero(t, r.Groups[0].CrawlDelay)
+
+	// Content past MaxSize is ignored
+	r = Parse([]byte("User-agent: *\n" + strings.Repeat("#", MaxSize) + "\nDisallow: /\n"))
+	assert.True(t, r.Allowed("bot", "/"))
+}
+
+func TestRobots_Group(t *testing.T) {
+	r := Parse([]byte(sample))
+
+	g := r.Group("Mozilla/5.0 (compatible; MyBot/2.0; +https://example.com/bot)")
+	assert.Equal(t, []string{"mybot"}, g.Agents)
+	assert.Equal(t, []Rule{{Pattern: "/"}, {Allow: true, Pattern: "/blog/"}, {Pattern: "/blog/drafts"}}, g.Rules)
+	assert.Equal(t, 500*time.Millisecond, g.CrawlDelay)
+
+	assert.Equal(t, []string{"*"}, r.Group("Googlebot").Agents)
+	assert.Equal(t, []string{"*"}, r.Group("").Agents)
+	assert.Nil(t, Parse([]byte("User-agent: OtherBot\nDisallow: /")).Group("MyBot"))
+}
+
+func TestRobots_Allowed(t *testing.T) {
+	r := Parse([]byte(sample))
+	tests := []struct {
+		agent string
+		url   string
+		want  bool
+	}{
+		{"Googlebot", "/", true},
+		{"Googlebot", "/private/", false},
+		{"Googlebot", "/private/page?x=1", false},
+		{"Googlebot", "/private/public/page", true},
+		{"Googlebot", "https://example.com/files/report.pdf", false},
+		{"Googlebot", "/files/report.pdf?download=1", true},
+		{"Googlebot", "/robots.txt", true},
+		{"MyBot", "/", false},
+		{"MyBot", "https://example.com", false},
+		{"MyBot", "/blog/post", true},
+		{"MyBot", "/blog/drafts/1", false},
+		{"OtherBot", "/blog/drafts/1", true},
+		{"OtherBot", "/robots.txt", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.agent+" "+tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.want, r.Allowed(tt.agent, tt.url))
+		})
+	}
+}
+
+func TestRobots_AllowedPrecedence(t *testing.T) {
+	// Allow wins ties between rules of the same length
+	r := Parse([]byte("User-agent: *\nDisallow: /page\nAllow: /page\nDisallow: /*.php\nAllow: /index.php$\n"))
+	assert.True(t, r.Allowed("bot", "/page"))
+	assert.False(t, r.Allowed("bot", "/admin.php"))
+	assert.True(t, r.Allowed("bot", "/index.php"))
+	assert.False(t, r.Allowed("bot", "/index.php?x"))
+
+	// Patterns and paths compare equal whatever their encoding
+	r = Parse([]byte("User-agent: *\nDisallow: /café\nDisallow: /a%3cb\n"))
+	assert.False(t, r.Allowed("bot", "/caf%C3%A9/menu"))
+	assert.False(t, r.Allowed("bot", "/a%3Cb"))
+}
+
+func TestRobots_AllowAllDisallowAll(t *testing.T) {
+	assert.True(t, AllowAll().Allowed("bot", "/anything"))
+	assert.Zero(t, AllowAll().CrawlDelay("bot"))
+	assert.False(t, DisallowAll().Allowed("bot", "/anything"))
+	assert.True(t, DisallowAll().Allowed("bot", "/robots.txt"))
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"/", "/anything", true},
+		{"/fish", "/fish.html", true},
+		{"/fish", "/Fish", false},
+		{"/fish$", "/fish", true},
+		{"/fish$", "/fish/", false},
+		{"/*.php", "/dir/index.php?x", true},
+		{"/*.php$", "/dir/index.php?x", false},
+		{"/a*b*c", "/a-c-b", false},
+		{"/a*b*c", "/a-b-b-c", true},
+		{"/a*b*c$", "/abcbc", true},
+		{"*/private", "/x/private", true},
+		{"/*", "/", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, match(tt.pattern, tt.path), "%s %s", tt.pattern, tt.path)
+	}
+}
@@ -0,0 +1,57 @@
+package robots
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scheduler spaces the requests to each host. It is safe for concurrent use.
+type Scheduler struct {
+	minDelay time.Duration
+	maxDelay time.Duration
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+// NewScheduler creates a Scheduler spacing the requests to a host by at least minDelay.
+// Longer delays passed to Wait, such as Crawl-delay values, are capped to maxDelay unless it is zero.
+func NewScheduler(minDelay, maxDelay time.Duration) *Scheduler {
+	return &Scheduler{minDelay: minDelay, maxDelay: maxDelay, next: map[string]time.Time{}}
+}
+
+// Wait blocks until a request to host may start, then reserves the host for delay, raised to
+// the minimum delay and capped to the maximum one. It returns ctx.Err() if ctx is done first.
+func (s *Scheduler) Wait(ctx context.Context, host string, delay time.Duration) error {
+	if delay < s.minDelay {
+		delay = s.minDelay
+	}
+	if s.maxDelay > 0 && delay > s.maxDelay {
+		delay = s.maxDelay
+	}
+	host = strings.ToLower(host)
+
+	s.mu.Lock()
+	now := time.Now()
+	start := s.next[host]
+	if start.Before(now) {
+		start = now
+	}
+	s.next[host] = start.Add(delay)
+	s.mu.Unlock()
+
+	wait := time.Until(start)
+	if wait <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
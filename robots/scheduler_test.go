@@ -0,0 +1,42 @@
+package robots
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_Wait(t *testing.T) {
+	s := NewScheduler(0, 0)
+	start := time.Now()
+	require.NoError(t, s.Wait(context.Background(), "example.com", 30*time.Millisecond))
+	require.NoError(t, s.Wait(context.Background(), "other.com", 30*time.Millisecond))
+	assert.Less(t, time.Since(start), 20*time.Millisecond)
+
+	// The second request to a host waits for the delay of the first one
+	require.NoError(t, s.Wait(context.Background(), "EXAMPLE.com", 0))
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+}
+
+func TestScheduler_Bounds(t *testing.T) {
+	s := NewScheduler(20*time.Millisecond, 40*time.Millisecond)
+	start := time.Now()
+	require.NoError(t, s.Wait(context.Background(), "example.com", 0))
+	require.NoError(t, s.Wait(context.Background(), "example.com", time.Hour))
+	require.NoError(t, s.Wait(context.Background(), "example.com", 0))
+	elapsed := time.Since(start)
+	assert.GreaterOrEqual(t, elapsed, 60*time.Millisecond)
+	assert.Less(t, elapsed, time.Second)
+}
+
+func TestScheduler_Cancel(t *testing.T) {
+	s := NewScheduler(time.Hour, 0)
+	require.NoError(t, s.Wait(context.Background(), "example.com", 0))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, s.Wait(ctx, "example.com", 0), context.DeadlineExceeded)
+}
@@ -0,0 +1,243 @@
+package ujeebu
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// RuleType is the type of value an extract rule returns
+type RuleType string
+
+const (
+	// RuleText extracts the text content of the matched element
+	RuleText RuleType = "text"
+	// RuleLink extracts the href of the matched element
+	RuleLink RuleType = "link"
+	// RuleImage extracts the src of the matched element
+	RuleImage RuleType = "image"
+	// RuleAttr extracts an attribute of the matched element
+	RuleAttr RuleType = "attr"
+	// RuleObj extracts an object made of child rules applied to the matched element
+	RuleObj RuleType = "obj"
+)
+
+// ExtractRule describes how the Scrape API extracts a value from a page
+type ExtractRule struct {
+	Selector  string       `json:"selector"`
+	Type      RuleType     `json:"type"`
+	Multiple  bool         `json:"multiple,omitempty"`
+	Attribute string       `json:"attribute,omitempty"`
+	Children  ExtractRules `json:"children,omitempty"`
+}
+
+// ExtractRules is a set of named extract rules for ScrapeParams.ExtractRules
+type ExtractRules map[string]*ExtractRule
+
+// Validate checks the rules for unknown types, missing selectors and missing attributes
+func (r ExtractRules) Validate() error {
+	if len(r) == 0 {
+		return &ValidationError{Field: "ExtractRules", Message: "at least one rule is required"}
+	}
+	return r.validate("ExtractRules")
+}
+
+func (r ExtractRules) validate(path string) error {
+	for _, name := range r.names() {
+		rule := r[name]
+		field := path + "." + name
+		if name == "" {
+			return &ValidationError{Field: path, Message: "rule name is required"}
+		}
+		if rule == nil {
+			return &ValidationError{Field: field, Message: "rule is nil"}
+		}
+		if rule.Selector == "" {
+			return &ValidationError{Field: field + ".selector", Message: "selector is required"}
+		}
+
+		switch rule.Type {
+		case RuleText, RuleLink, RuleImage:
+		case RuleAttr:
+			if rule.Attribute == "" {
+				return &ValidationError{Field: field + ".attribute", Message: "attribute is required for attr rules"}
+			}
+		case RuleObj:
+			if len(rule.Children) == 0 {
+				return &ValidationError{Field: field + ".children", Message: "children are required for obj rules"}
+			}
+			if err := rule.Children.validate(field + ".children"); err != nil {
+				return err
+			}
+		case "":
+			return &ValidationError{Field: field + ".type", Message: "type is required"}
+		default:
+			return &ValidationError{Field: field + ".type", Message: fmt.Sprintf("unknown rule type '%s'", rule.Type)}
+		}
+
+		if rule.Type != RuleAttr && rule.Attribute != "" {
+			return &ValidationError{Field: field + ".attribute", Message: "attribute is only allowed for attr rules"}
+		}
+		if rule.Type != RuleObj && len(rule.Children) > 0 {
+			return &ValidationError{Field: field + ".children", Message: "children are only allowed for obj rules"}
+		}
+	}
+	return nil
+}
+
+// names returns the rule names in a stable order
+func (r ExtractRules) names() []string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Map converts the rules to the map expected by ScrapeParams.ExtractRules
+func (r ExtractRules) Map() map[string]any {
+	m := make(map[string]any, len(r))
+	for name, rule := range r {
+		if rule == nil {
+			continue
+		}
+		v := map[string]any{
+			"selector": rule.Selector,
+			"type":     string(rule.Type),
+		}
+		if rule.Multiple {
+			v["multiple"] = true
+		}
+		if rule.Attribute != "" {
+			v["attribute"] = rule.Attribute
+		}
+		if len(rule.Children) > 0 {
+			v["children"] = rule.Children.Map()
+		}
+		m[name] = v
+	}
+	return m
+}
+
+// ParseExtractRules converts a raw extract_rules map into typed rules and validates them
+func ParseExtractRules(m map[string]any) (ExtractRules, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode extract rules: %w", err)
+	}
+	return UnmarshalExtractRules(data)
+}
+
+// UnmarshalExtractRules decodes JSON extract rules and validates them.
+// Unknown rule fields are rejected.
+func UnmarshalExtractRules(data []byte) (ExtractRules, error) {
+	var rules ExtractRules
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&rules); err != nil {
+		return nil, fmt.Errorf("failed to parse extract rules: %w", err)
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// LoadExtractRules reads and validates extract rules from a JSON file
+func LoadExtractRules(path string) (ExtractRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalExtractRules(data)
+}
+
+// Save validates the rules and writes them to a JSON file
+func (r ExtractRules) Save(path string) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// RulesBuilder builds ExtractRules fluently
+//
+//	rules, err := ujeebu.NewRules().
+//		Text("title", "h1").
+//		Link("links", "a").Multiple().
+//		Obj("products", ".product", func(b *ujeebu.RulesBuilder) {
+//			b.Text("name", ".name").Attr("sku", ".name", "data-sku")
+//		}).Multiple().
+//		Build()
+type RulesBuilder struct {
+	rules ExtractRules
+	last  string
+}
+
+// NewRules returns an empty RulesBuilder
+func NewRules() *RulesBuilder {
+	return &RulesBuilder{rules: ExtractRules{}}
+}
+
+func (b *RulesBuilder) add(name string, rule *ExtractRule) *RulesBuilder {
+	b.rules[name] = rule
+	b.last = name
+	return b
+}
+
+// Text adds a rule extracting the text of the elements matching selector
+func (b *RulesBuilder) Text(name, selector string) *RulesBuilder {
+	return b.add(name, &ExtractRule{Selector: selector, Type: RuleText})
+}
+
+// Link adds a rule extracting the href of the elements matching selector
+func (b *RulesBuilder) Link(name, selector string) *RulesBuilder {
+	return b.add(name, &ExtractRule{Selector: selector, Type: RuleLink})
+}
+
+// Image adds a rule extracting the src of the elements matching selector
+func (b *RulesBuilder) Image(name, selector string) *RulesBuilder {
+	return b.add(name, &ExtractRule{Selector: selector, Type: RuleImage})
+}
+
+// Attr adds a rule extracting attribute of the elements matching selector
+func (b *RulesBuilder) Attr(name, selector, attribute string) *RulesBuilder {
+	return b.add(name, &ExtractRule{Selector: selector, Type: RuleAttr, Attribute: attribute})
+}
+
+// Obj adds a rule extracting an object whose fields are defined by children
+func (b *RulesBuilder) Obj(name, selector string, children func(*RulesBuilder)) *RulesBuilder {
+	child := NewRules()
+	if children != nil {
+		children(child)
+	}
+	return b.add(name, &ExtractRule{Selector: selector, Type: RuleObj, Children: child.rules})
+}
+
+// Multiple makes the last added rule return all matching elements instead of the first one
+func (b *RulesBuilder) Multiple() *RulesBuilder {
+	if rule, ok := b.rules[b.last]; ok {
+		rule.Multiple = true
+	}
+	return b
+}
+
+// Rules returns the typed rules built so far without validating them
+func (b *RulesBuilder) Rules() ExtractRules {
+	return b.rules
+}
+
+// Build validates the rules and returns the map for ScrapeParams.ExtractRules
+func (b *RulesBuilder) Build() (map[string]any, error) {
+	if err := b.rules.Validate(); err != nil {
+		return nil, err
+	}
+	return b.rules.Map(), nil
+}
@@ -0,0 +1,135 @@
+package ujeebu
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRulesBuilder_Build(t *testing.T) {
+	rules, err := NewRules().
+		Text("title", "h1").
+		Link("links", "a").Multiple().
+		Image("hero", "img.hero").
+		Obj("products", ".product", func(b *RulesBuilder) {
+			b.Text("name", ".name").
+				Attr("sku", ".name", "data-sku")
+		}).Multiple().
+		Build()
+	require.NoError(t, err)
+
+	expected := map[string]any{
+		"title": map[string]any{"selector": "h1", "type": "text"},
+		"links": map[string]any{"selector": "a", "type": "link", "multiple": true},
+		"hero":  map[string]any{"selector": "img.hero", "type": "image"},
+		"products": map[string]any{
+			"selector": ".product",
+			"type":     "obj",
+			"multiple": true,
+			"children": map[string]any{
+				"name": map[string]any{"selector": ".name", "type": "text"},
+				"sku":  map[string]any{"selector": ".name", "type": "attr", "attribute": "data-sku"},
+			},
+		},
+	}
+	assert.Equal(t, expected, rules)
+}
+
+func TestExtractRules_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules ExtractRules
+		field string
+	}{
+		{
+			name:  "empty",
+			rules: ExtractRules{},
+			field: "ExtractRules",
+		},
+		{
+			name:  "missing selector",
+			rules: ExtractRules{"title": {Type: RuleText}},
+			field: "ExtractRules.title.selector",
+		},
+		{
+			name:  "unknown type",
+			rules: ExtractRules{"title": {Selector: "h1", Type: "txt"}},
+			field: "ExtractRules.title.type",
+		},
+		{
+			name:  "missing type",
+			rules: ExtractRules{"title": {Selector: "h1"}},
+			field: "ExtractRules.title.type",
+		},
+		{
+			name:  "attr without attribute",
+			rules: ExtractRules{"id": {Selector: "div", Type: RuleAttr}},
+			field: "ExtractRules.id.attribute",
+		},
+		{
+			name:  "attribute on text rule",
+			rules: ExtractRules{"id": {Selector: "div", Type: RuleText, Attribute: "id"}},
+			field: "ExtractRules.id.attribute",
+		},
+		{
+			name:  "obj without children",
+			rules: ExtractRules{"item": {Selector: ".item", Type: RuleObj}},
+			field: "ExtractRules.item.children",
+		},
+		{
+			name: "invalid nested child",
+			rules: ExtractRules{"item": {Selector: ".item", Type: RuleObj, Children: ExtractRules{
+				"price": {Type: RuleText},
+			}}},
+			field: "ExtractRules.item.children.price.selector",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rules.Validate()
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tc.field, validationErr.Field)
+		})
+	}
+}
+
+func TestParseExtractRules(t *testing.T) {
+	rules, err := ParseExtractRules(map[string]any{
+		"title": map[string]any{"selector": "h1", "type": "text"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, RuleText, rules["title"].Type)
+
+	_, err = ParseExtractRules(map[string]any{
+		"title": map[string]any{"_selector": "h1", "type": "text"},
+	})
+	assert.ErrorContains(t, err, "_selector")
+}
+
+func TestExtractRules_SaveAndLoad(t *testing.T) {
+	rules := NewRules().
+		Text("title", "h1").
+		Obj("author", ".author", func(b *RulesBuilder) {
+			b.Text("name", ".name").Link("profile", "a")
+		}).
+		Rules()
+
+	path := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, rules.Save(path))
+
+	loaded, err := LoadExtractRules(path)
+	require.NoError(t, err)
+	assert.Equal(t, rules, loaded)
+	assert.Equal(t, rules.Map(), loaded.Map())
+}
+
+func TestExtractRules_SaveInvalid(t *testing.T) {
+	rules := ExtractRules{"title": {Selector: "h1", Type: "bogus"}}
+	err := rules.Save(filepath.Join(t.TempDir(), "rules.json"))
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
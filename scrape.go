@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/go-resty/resty/v2"
 )
@@ -40,32 +41,54 @@ type ScrapeResponse struct {
 
 // Scrape calls the Ujeebu Scrape API and returns structured JSON response
 func (c *Client) Scrape(params ScrapeParams) (*ScrapeResponse, int, error) {
+	scrapeResp, meta, err := c.ScrapeWithMeta(context.Background(), params)
+	return scrapeResp, meta.Credits, err
+}
+
+// ScrapeWithMeta calls the Ujeebu Scrape API with context support and returns
+// the structured JSON response along with the call metadata
+func (c *Client) ScrapeWithMeta(ctx context.Context, params ScrapeParams) (*ScrapeResponse, ResponseMeta, error) {
 	// Force JSON output for structured response
 	params.JSONOutput = true
 
-	rawResp, credits, err := c.ScrapeWithContext(context.Background(), params)
+	rawResp, meta, err := c.ScrapeRawWithMeta(ctx, params)
 	if err != nil {
-		return nil, credits, err
+		return nil, meta, err
 	}
 
 	// Parse JSON response into ScrapeResponse
 	var scrapeResp ScrapeResponse
 	if err := json.Unmarshal(rawResp.Body, &scrapeResp); err != nil {
-		return nil, credits, fmt.Errorf("failed to parse JSON response: %w", err)
+		return nil, meta, fmt.Errorf("failed to parse JSON response: %w", err)
 	}
 
 	// Populate HTTP metadata
 	scrapeResp.StatusCode = rawResp.StatusCode
 	scrapeResp.ResponseHeaders = rawResp.Headers
 
-	return &scrapeResp, credits, nil
+	return &scrapeResp, meta, nil
 }
 
 // ScrapeWithContext calls the Ujeebu Scrape API with context support and returns raw response
 func (c *Client) ScrapeWithContext(ctx context.Context, params ScrapeParams) (*RawScrapeResponse, int, error) {
+	rawResp, meta, err := c.ScrapeRawWithMeta(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	return rawResp, meta.Credits, nil
+}
+
+// ScrapeRawWithMeta calls the Ujeebu Scrape API and returns the raw response along with the call metadata
+func (c *Client) ScrapeRawWithMeta(ctx context.Context, params ScrapeParams) (*RawScrapeResponse, ResponseMeta, error) {
+	return intercept(ctx, c, "scrape", params, c.scrapeRawWithMeta)
+}
+
+func (c *Client) scrapeRawWithMeta(ctx context.Context, params ScrapeParams) (*RawScrapeResponse, ResponseMeta, error) {
+	meta := ResponseMeta{Endpoint: "scrape"}
+
 	// Validate required parameters
 	if params.URL == "" {
-		return nil, 0, &ValidationError{
+		return nil, meta, &ValidationError{
 			Field:   "URL",
 			Message: "URL is required",
 		}
@@ -89,27 +112,25 @@ func (c *Client) ScrapeWithContext(ctx context.Context, params ScrapeParams) (*R
 		req.SetHeader("UJB-"+key, value)
 	}
 
-	req.SetError(&APIError{})
-	var resp *resty.Response
-	var err error
+	call := apiCall{
+		endpoint:   "scrape",
+		method:     resty.MethodGet,
+		path:       "/scrape",
+		idempotent: isIdempotentScrape(params),
+		metered:    true,
+	}
 
 	if params.ExtractRules != nil {
 		req.SetBody(params)
 		req.SetHeader("Content-Type", "application/json")
-		resp, err = req.Post("/scrape")
+		call.method = resty.MethodPost
 	} else {
 		req.SetQueryParamsFromValues(params.toMap())
-		resp, err = req.Get("/scrape")
 	}
 
+	resp, meta, err := c.execute(req, call)
 	if err != nil {
-		return nil, 0, &NetworkError{Err: err}
-	}
-
-	if resp.IsError() {
-		apiErr := resp.Error().(*APIError)
-		apiErr.StatusCode = resp.StatusCode()
-		return nil, 0, apiErr
+		return nil, meta, err
 	}
 
 	rawResp := &RawScrapeResponse{
@@ -118,7 +139,13 @@ func (c *Client) ScrapeWithContext(ctx context.Context, params ScrapeParams) (*R
 		Headers:    resp.Header(),
 	}
 
-	return rawResp, getUjeebuCreditsFromResponse(resp), nil
+	return rawResp, meta, nil
+}
+
+// isIdempotentScrape reports whether repeating the scrape has no side effects on the target site
+func isIdempotentScrape(params ScrapeParams) bool {
+	method := strings.ToUpper(params.HTTPMethod)
+	return params.PostData == "" && (method == "" || method == http.MethodGet || method == http.MethodHead)
 }
 
 // Screenshot retrieves the screenshot of the page with optional parameters.
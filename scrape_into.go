@@ -0,0 +1,167 @@
+package ujeebu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RulesTag is the struct tag read by RulesFor
+const RulesTag = "ujeebu"
+
+// RulesFor generates extract rules from the fields of struct type T tagged with `ujeebu`.
+// The tag holds the CSS selector, optionally followed by ";type=<type>" or ";attr=<attribute>":
+//
+//	type Product struct {
+//		Name   string   `json:"name" ujeebu:".product-title"`
+//		URL    string   `json:"url" ujeebu:"a.product-link;type=link"`
+//		SKU    string   `json:"sku" ujeebu:".product;attr=data-sku"`
+//		Images []string `json:"images" ujeebu:"img;type=image"`
+//	}
+//
+// Rule names follow the json tag (or the field name). Slice fields produce rules with
+// Multiple set, and struct fields produce obj rules whose children come from the nested struct.
+func RulesFor[T any]() (ExtractRules, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	rules, err := rulesForType(t, t.Name(), map[reflect.Type]bool{})
+	if err != nil {
+		return nil, err
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// rulesForType generates the rules of struct type t. visiting holds the struct types
+// on the current path, as recursive types cannot be expressed as extract rules.
+func rulesForType(t reflect.Type, path string, visiting map[reflect.Type]bool) (ExtractRules, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, &ValidationError{Field: path, Message: fmt.Sprintf("extract rules require a struct type, got %s", t.Kind())}
+	}
+	if visiting[t] {
+		return nil, &ValidationError{Field: path, Message: fmt.Sprintf("recursive type %s cannot be expressed as extract rules", t)}
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	rules := ExtractRules{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup(RulesTag)
+		if !ok || tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		name := jsonFieldName(field)
+		if name == "" {
+			continue
+		}
+
+		rule, err := parseRuleTag(tag)
+		if err != nil {
+			return nil, &ValidationError{Field: path + "." + field.Name, Message: err.Error()}
+		}
+
+		ft := field.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array {
+			rule.Multiple = true
+			ft = ft.Elem()
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+		}
+		if ft.Kind() == reflect.Struct {
+			if rule.Type != "" && rule.Type != RuleObj {
+				return nil, &ValidationError{Field: path + "." + field.Name, Message: "struct fields must use obj rules"}
+			}
+			children, err := rulesForType(ft, path+"."+field.Name, visiting)
+			if err != nil {
+				return nil, err
+			}
+			rule.Type = RuleObj
+			rule.Children = children
+		}
+		if rule.Type == "" {
+			rule.Type = RuleText
+		}
+
+		rules[name] = rule
+	}
+	return rules, nil
+}
+
+// parseRuleTag parses a `ujeebu:"selector;type=link;attr=href"` tag
+func parseRuleTag(tag string) (*ExtractRule, error) {
+	parts := strings.Split(tag, ";")
+	rule := &ExtractRule{Selector: strings.TrimSpace(parts[0])}
+	for _, opt := range parts[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(opt), "=")
+		switch strings.TrimSpace(key) {
+		case "type":
+			rule.Type = RuleType(strings.TrimSpace(value))
+		case "attr":
+			rule.Type = RuleAttr
+			rule.Attribute = strings.TrimSpace(value)
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown rule tag option '%s'", key)
+		}
+	}
+	return rule, nil
+}
+
+// jsonFieldName returns the JSON name of a struct field, empty if it is skipped
+func jsonFieldName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// ScrapeInto scrapes a page with extract rules and decodes the result into T.
+// If params.ExtractRules is nil, the rules are generated from T with RulesFor.
+func ScrapeInto[T any](ctx context.Context, c *Client, params ScrapeParams) (T, ResponseMeta, error) {
+	var out T
+
+	if params.ExtractRules == nil {
+		rules, err := RulesFor[T]()
+		if err != nil {
+			return out, ResponseMeta{Endpoint: "scrape"}, err
+		}
+		params.ExtractRules = rules.Map()
+	}
+	params.JSONOutput = true
+
+	rawResp, meta, err := c.ScrapeRawWithMeta(ctx, params)
+	if err != nil {
+		return out, meta, err
+	}
+
+	var payload struct {
+		Success bool            `json:"success"`
+		Result  json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(rawResp.Body, &payload); err != nil {
+		return out, meta, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+	if len(payload.Result) == 0 || string(payload.Result) == "null" {
+		return out, meta, fmt.Errorf("scrape response has no extract_rules result")
+	}
+	if err := json.Unmarshal(payload.Result, &out); err != nil {
+		return out, meta, fmt.Errorf("failed to decode extract_rules result into %T: %w", out, err)
+	}
+	return out, meta, nil
+}
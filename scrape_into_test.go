@@ -0,0 +1,127 @@
+package ujeebu
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testProduct struct {
+	Name  string   `json:"name" ujeebu:".name"`
+	URL   string   `json:"url" ujeebu:"a;type=link"`
+	SKU   string   `json:"sku" ujeebu:".name;attr=data-sku"`
+	Tags  []string `json:"tags" ujeebu:".tag"`
+	Notes string   `json:"notes"`
+}
+
+type testCatalog struct {
+	Title    string        `json:"title" ujeebu:"h1, h2"`
+	Hero     string        `json:"hero,omitempty" ujeebu:"img.hero;type=image"`
+	Products []testProduct `json:"products" ujeebu:".product"`
+}
+
+func TestRulesFor(t *testing.T) {
+	rules, err := RulesFor[testCatalog]()
+	require.NoError(t, err)
+
+	expected := ExtractRules{
+		"title": {Selector: "h1, h2", Type: RuleText},
+		"hero":  {Selector: "img.hero", Type: RuleImage},
+		"products": {Selector: ".product", Type: RuleObj, Multiple: true, Children: ExtractRules{
+			"name": {Selector: ".name", Type: RuleText},
+			"url":  {Selector: "a", Type: RuleLink},
+			"sku":  {Selector: ".name", Type: RuleAttr, Attribute: "data-sku"},
+			"tags": {Selector: ".tag", Type: RuleText, Multiple: true},
+		}},
+	}
+	assert.Equal(t, expected, rules)
+}
+
+func TestRulesFor_Errors(t *testing.T) {
+	_, err := RulesFor[string]()
+	assert.Error(t, err)
+
+	type badType struct {
+		Title string `json:"title" ujeebu:"h1;type=txt"`
+	}
+	_, err = RulesFor[badType]()
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "ExtractRules.title.type", validationErr.Field)
+
+	type badOption struct {
+		Title string `json:"title" ujeebu:"h1;kind=text"`
+	}
+	_, err = RulesFor[badOption]()
+	assert.ErrorContains(t, err, "unknown rule tag option")
+
+	type node struct {
+		Title    string `json:"title" ujeebu:".title"`
+		Children []node `json:"children" ujeebu:".child"`
+	}
+	_, err = RulesFor[node]()
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "node.Children", validationErr.Field)
+	assert.ErrorContains(t, err, "recursive type")
+
+	// A struct type used by several fields is not a cycle
+	type comparison struct {
+		Left  testProduct  `json:"left" ujeebu:".left"`
+		Right *testProduct `json:"right" ujeebu:".right"`
+	}
+	_, err = RulesFor[comparison]()
+	assert.NoError(t, err)
+}
+
+func TestScrapeInto(t *testing.T) {
+	var sentRules map[string]any
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req map[string]any
+		_ = json.Unmarshal(body, &req)
+		sentRules, _ = req["extract_rules"].(map[string]any)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(CreditsHeader, "5")
+		_, _ = w.Write([]byte(`{
+			"success": true,
+			"result": {
+				"title": "Catalog",
+				"products": [
+					{"name": "Widget", "url": "https://example.com/w", "sku": "W-1", "tags": ["new", "sale"]},
+					{"name": "Gadget", "url": "https://example.com/g", "sku": "G-2", "tags": []}
+				]
+			}
+		}`))
+	}))
+	defer mockServer.Close()
+
+	client, err := NewClient("test_api_key", WithBaseURL(mockServer.URL))
+	require.NoError(t, err)
+
+	catalog, meta, err := ScrapeInto[testCatalog](context.Background(), client, ScrapeParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "Catalog", catalog.Title)
+	require.Len(t, catalog.Products, 2)
+	assert.Equal(t, "W-1", catalog.Products[0].SKU)
+	assert.Equal(t, []string{"new", "sale"}, catalog.Products[0].Tags)
+	assert.Equal(t, 5, meta.Credits)
+
+	require.NotNil(t, sentRules)
+	assert.Contains(t, sentRules, "products")
+	assert.Equal(t, "h1, h2", sentRules["title"].(map[string]any)["selector"])
+}
+
+func TestScrapeInto_NoResult(t *testing.T) {
+	mockServer, client := setupMockScrapeServer(`{"success": true}`, map[string]string{}, "application/json", http.StatusOK)
+	defer mockServer.Close()
+
+	_, _, err := ScrapeInto[testCatalog](context.Background(), client, ScrapeParams{URL: "https://example.com"})
+	assert.ErrorContains(t, err, "no extract_rules result")
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
+
+	"github.com/go-resty/resty/v2"
 )
 
 type ResponseMetadata struct {
@@ -13,31 +16,17 @@ type ResponseMetadata struct {
 	ResultsTime     string `json:"results_time"`
 }
 
+// PaginationLinks holds the links to the current, next and other result pages
+type PaginationLinks struct {
+	Current string `json:"current"`
+	Next    string `json:"next"`
+	// OtherPages maps page numbers to their links
+	OtherPages map[int]string `json:"other_pages"`
+}
+
 type ResponsePagination struct {
-	Google struct {
-		Current    string `json:"current"`
-		Next       string `json:"next"`
-		OtherPages struct {
-			Field1 string `json:"3"`
-			Field2 string `json:"4"`
-			Field3 string `json:"5"`
-			Field4 string `json:"6"`
-			Field5 string `json:"7"`
-			Field6 string `json:"8"`
-		} `json:"other_pages"`
-	} `json:"google"`
-	Api struct {
-		Current    string `json:"current"`
-		Next       string `json:"next"`
-		OtherPages struct {
-			Field1 string `json:"3"`
-			Field2 string `json:"4"`
-			Field3 string `json:"5"`
-			Field4 string `json:"6"`
-			Field5 string `json:"7"`
-			Field6 string `json:"8"`
-		} `json:"other_pages"`
-	} `json:"api"`
+	Google PaginationLinks `json:"google"`
+	Api    PaginationLinks `json:"api"`
 }
 
 type BaseResponse struct {
@@ -55,6 +44,10 @@ type KnowledgeGraph struct {
 	Siblings  string `json:"siblings,omitempty"`
 	Title     string `json:"title,omitempty"`
 	Type      string `json:"type,omitempty"`
+	// Attributes holds every scalar attribute of the knowledge graph, including the ones above
+	Attributes map[string]string `json:"-"`
+	// Extra holds the non-scalar fields not modeled by this type
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // OrganicResult represents the structure for individual organic search results
@@ -65,6 +58,8 @@ type OrganicResult struct {
 	SiteName    string `json:"site_name,omitempty"`
 	Title       string `json:"title,omitempty"`
 	Description string `json:"description,omitempty"`
+	// Extra holds the fields not modeled by this type
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // SearchVideo represents the structure for video search results
@@ -73,6 +68,8 @@ type SearchVideo struct {
 	Date   string `json:"date,omitempty"`
 	Link   string `json:"link,omitempty"`
 	Title  string `json:"title,omitempty"`
+	// Extra holds the fields not modeled by this type
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type TopStory struct {
@@ -80,16 +77,80 @@ type TopStory struct {
 	SiteName    string `json:"siteName,omitempty"`
 	Description string `json:"description,omitempty"`
 	PubDate     string `json:"pubDate,omitempty"`
+	// Extra holds the fields not modeled by this type
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// Ad represents a paid search result
+type Ad struct {
+	Position      int    `json:"position,omitempty"`
+	Block         string `json:"block,omitempty"` // "top" or "bottom"
+	Title         string `json:"title,omitempty"`
+	Link          string `json:"link,omitempty"`
+	DisplayedLink string `json:"displayed_link,omitempty"`
+	Description   string `json:"description,omitempty"`
+	// Extra holds the fields not modeled by this type
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// FeaturedSnippet represents the answer box shown above the organic results
+type FeaturedSnippet struct {
+	Title       string   `json:"title,omitempty"`
+	Link        string   `json:"link,omitempty"`
+	SiteName    string   `json:"site_name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	List        []string `json:"list,omitempty"`
+	// Extra holds the fields not modeled by this type
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// PeopleAlsoAsk represents a question of the "People also ask" block
+type PeopleAlsoAsk struct {
+	Question string `json:"question,omitempty"`
+	Answer   string `json:"answer,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Link     string `json:"link,omitempty"`
+	// Extra holds the fields not modeled by this type
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// LocalResult represents a place of the local pack
+type LocalResult struct {
+	Position int     `json:"position,omitempty"`
+	Title    string  `json:"title,omitempty"`
+	Address  string  `json:"address,omitempty"`
+	Category string  `json:"category,omitempty"`
+	Phone    string  `json:"phone,omitempty"`
+	Rating   float64 `json:"rating,omitempty"`
+	Reviews  int     `json:"reviews,omitempty"`
+	Link     string  `json:"link,omitempty"`
+	// Extra holds the fields not modeled by this type
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// RelatedSearch represents a suggested related query
+type RelatedSearch struct {
+	Query string `json:"query,omitempty"`
+	Link  string `json:"link,omitempty"`
+	// Extra holds the fields not modeled by this type
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // GoogleSearchResult extends BaseResponse to include additional JSON fields
 type GoogleSearchResult struct {
 	BaseResponse
-	KnowledgeGraph   KnowledgeGraph  `json:"knowledge_graph"`
-	OrganicResults   []OrganicResult `json:"organic_results"`
-	RelatedQuestions []string        `json:"related_questions,omitempty"`
-	TopStories       []TopStory      `json:"top_stories,omitempty"`
-	Videos           []SearchVideo   `json:"videos,omitempty"`
+	KnowledgeGraph   KnowledgeGraph   `json:"knowledge_graph"`
+	OrganicResults   []OrganicResult  `json:"organic_results"`
+	RelatedQuestions []string         `json:"related_questions,omitempty"`
+	TopStories       []TopStory       `json:"top_stories,omitempty"`
+	Videos           []SearchVideo    `json:"videos,omitempty"`
+	Ads              []Ad             `json:"ads,omitempty"`
+	FeaturedSnippet  *FeaturedSnippet `json:"featured_snippet,omitempty"`
+	PeopleAlsoAsk    []PeopleAlsoAsk  `json:"people_also_ask,omitempty"`
+	LocalResults     []LocalResult    `json:"local_results,omitempty"`
+	RelatedSearches  []RelatedSearch  `json:"related_searches,omitempty"`
+	// Extra holds the sections not modeled by this type
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type News struct {
@@ -101,12 +162,16 @@ type News struct {
 	Position    int    `json:"position,omitempty"`
 	SiteName    string `json:"siteName,omitempty"`
 	Title       string `json:"title,omitempty"`
+	// Extra holds the fields not modeled by this type
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // GoogleNewsResult represents metadata for a news search result with pagination
 type GoogleNewsResult struct {
 	BaseResponse
 	News []News `json:"news,omitempty"`
+	// Extra holds the fields not modeled by this type
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type GoogleVideo struct {
@@ -119,11 +184,15 @@ type GoogleVideo struct {
 	Summary     string `json:"summary"`
 	Title       string `json:"title"`
 	Url         string `json:"url"`
+	// Extra holds the fields not modeled by this type
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type GoogleVideosResult struct {
 	BaseResponse
 	Videos []GoogleVideo `json:"videos,omitempty"`
+	// Extra holds the fields not modeled by this type
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type GoogleImage struct {
@@ -135,6 +204,8 @@ type GoogleImage struct {
 	Source          string `json:"source"`
 	Title           string `json:"title"`
 	Width           int    `json:"width"`
+	// Extra holds the fields not modeled by this type
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type GoogleImageSuggestion struct {
@@ -143,12 +214,16 @@ type GoogleImageSuggestion struct {
 	Thumbnail  string `json:"thumbnail"`
 	Title      string `json:"title"`
 	UjeebuLink string `json:"ujeebu_link"`
+	// Extra holds the fields not modeled by this type
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type GoogleImagesResult struct {
 	BaseResponse
 	Images      []GoogleImage           `json:"images,omitempty"`
 	Suggestions []GoogleImageSuggestion `json:"suggestions,omitempty"`
+	// Extra holds the fields not modeled by this type
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type GoogleMap struct {
@@ -160,11 +235,15 @@ type GoogleMap struct {
 	Rating       float64     `json:"rating"`
 	Reviews      int         `json:"reviews"`
 	Title        string      `json:"title"`
+	// Extra holds the fields not modeled by this type
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type GoogleMapsResult struct {
 	BaseResponse
 	Maps []GoogleMap `json:"maps_results,omitempty"`
+	// Extra holds the fields not modeled by this type
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // SerpParams represents the parameters used for the SERP API
@@ -187,30 +266,55 @@ func (c *Client) Serp(params SerpParams) ([]byte, int, error) {
 
 // SerpWithContext retrieves search results with context support
 func (c *Client) SerpWithContext(ctx context.Context, params SerpParams) ([]byte, int, error) {
+	body, meta, err := c.SerpWithMeta(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	return body, meta.Credits, nil
+}
+
+// SerpWithMeta retrieves raw search results along with the call metadata
+func (c *Client) SerpWithMeta(ctx context.Context, params SerpParams) ([]byte, ResponseMeta, error) {
+	return intercept(ctx, c, "serp", params, c.serpWithMeta)
+}
+
+func (c *Client) serpWithMeta(ctx context.Context, params SerpParams) ([]byte, ResponseMeta, error) {
+	meta := ResponseMeta{Endpoint: "serp"}
+
 	// Validate required parameters - at least one of Search or URL must be provided
 	if params.Search == "" && params.URL == "" {
-		return nil, 0, &ValidationError{
+		return nil, meta, &ValidationError{
 			Field:   "Search/URL",
 			Message: "Either Search or URL parameter is required",
 		}
 	}
 
-	req := c.newRequest(ctx)
-	req.SetQueryParams(serpParamsToMap(params))
-	req.SetError(&APIError{})
-
-	resp, err := req.Get("/serp")
-	if err != nil {
-		return nil, 0, &NetworkError{Err: err}
+	query := serpParamsToMap(params)
+	values := url.Values{}
+	for name, value := range query {
+		values.Set(name, value)
+	}
+	cacheKey, cached, cachedMeta, hit := c.cacheLookup(ctx, "serp", values, nil)
+	if hit {
+		return cached, cachedMeta, nil
 	}
 
-	if resp.IsError() {
-		apiErr := resp.Error().(*APIError)
-		apiErr.StatusCode = resp.StatusCode()
-		return nil, 0, apiErr
+	req := c.newRequest(ctx)
+	req.SetQueryParams(query)
+
+	resp, meta, err := c.execute(req, apiCall{
+		endpoint:   "serp",
+		method:     resty.MethodGet,
+		path:       "/serp",
+		idempotent: true,
+		metered:    true,
+	})
+	if err != nil {
+		return nil, meta, err
 	}
 
-	return resp.Body(), getUjeebuCreditsFromResponse(resp), nil
+	c.cacheStore("serp", cacheKey, resp.Body())
+	return resp.Body(), meta, nil
 }
 
 // Helper function to convert SerpParams into a query parameter map
@@ -252,85 +356,88 @@ func serpParamsToMap(params SerpParams) map[string]string {
 
 // GoogleSearch Method for performing a Google Search
 func (c *Client) GoogleSearch(params SerpParams) (GoogleSearchResult, int, error) {
-	response, credits, err := c.Serp(params)
-	if err != nil {
-		return GoogleSearchResult{}, 0, err
-	}
+	return c.GoogleSearchWithContext(context.Background(), params)
+}
 
-	// Deserialize JSON response "results" into specific type
-	var results GoogleSearchResult
-	err = json.Unmarshal(response, &results)
+// GoogleSearchWithContext performs a Google Search with context support
+func (c *Client) GoogleSearchWithContext(ctx context.Context, params SerpParams) (GoogleSearchResult, int, error) {
+	results, meta, err := searchGoogle[GoogleSearchResult](ctx, c, params, "Google Search")
 	if err != nil {
-		return GoogleSearchResult{}, 0, fmt.Errorf("failed to parse Google Search results: %w", err)
+		return GoogleSearchResult{}, 0, err
 	}
-
-	return results, credits, nil
+	return results, meta.Credits, nil
 }
 
 // GoogleImageSearch Method for performing a Google GoogleImage Search
 func (c *Client) GoogleImageSearch(params SerpParams) (GoogleImagesResult, int, error) {
-	params.SearchType = "images"
-	response, credits, err := c.Serp(params)
-	if err != nil {
-		return GoogleImagesResult{}, 0, err
-	}
+	return c.GoogleImageSearchWithContext(context.Background(), params)
+}
 
-	var results GoogleImagesResult
-	err = json.Unmarshal(response, &results)
+// GoogleImageSearchWithContext performs a Google Image Search with context support
+func (c *Client) GoogleImageSearchWithContext(ctx context.Context, params SerpParams) (GoogleImagesResult, int, error) {
+	params.SearchType = "images"
+	results, meta, err := searchGoogle[GoogleImagesResult](ctx, c, params, "Google GoogleImage")
 	if err != nil {
-		return results, 0, fmt.Errorf("failed to parse Google GoogleImage results: %w", err)
+		return results, 0, err
 	}
-
-	return results, credits, nil
+	return results, meta.Credits, nil
 }
 
 // GoogleNewsSearch Method for performing a Google News Search
 func (c *Client) GoogleNewsSearch(params SerpParams) (GoogleNewsResult, int, error) {
-	params.SearchType = "news"
-	response, credits, err := c.Serp(params)
-	if err != nil {
-		return GoogleNewsResult{}, 0, err
-	}
+	return c.GoogleNewsSearchWithContext(context.Background(), params)
+}
 
-	var results GoogleNewsResult
-	err = json.Unmarshal(response, &results)
+// GoogleNewsSearchWithContext performs a Google News Search with context support
+func (c *Client) GoogleNewsSearchWithContext(ctx context.Context, params SerpParams) (GoogleNewsResult, int, error) {
+	params.SearchType = "news"
+	results, meta, err := searchGoogle[GoogleNewsResult](ctx, c, params, "Google News")
 	if err != nil {
-		return results, 0, fmt.Errorf("failed to parse Google News results: %w", err)
+		return results, 0, err
 	}
-
-	return results, credits, nil
+	return results, meta.Credits, nil
 }
 
 // GoogleVideoSearch Method for performing a Google SearchVideo Search
 func (c *Client) GoogleVideoSearch(params SerpParams) (GoogleVideosResult, int, error) {
-	params.SearchType = "videos"
-	response, credits, err := c.Serp(params)
-	if err != nil {
-		return GoogleVideosResult{}, 0, err
-	}
+	return c.GoogleVideoSearchWithContext(context.Background(), params)
+}
 
-	var results GoogleVideosResult
-	err = json.Unmarshal(response, &results)
+// GoogleVideoSearchWithContext performs a Google Video Search with context support
+func (c *Client) GoogleVideoSearchWithContext(ctx context.Context, params SerpParams) (GoogleVideosResult, int, error) {
+	params.SearchType = "videos"
+	results, meta, err := searchGoogle[GoogleVideosResult](ctx, c, params, "Google SearchVideo")
 	if err != nil {
-		return results, 0, fmt.Errorf("failed to parse Google SearchVideo results: %w", err)
+		return results, 0, err
 	}
-
-	return results, credits, nil
+	return results, meta.Credits, nil
 }
 
 // GoogleMapSearch Method for performing a Google Maps Search
 func (c *Client) GoogleMapSearch(params SerpParams) (GoogleMapsResult, int, error) {
+	return c.GoogleMapSearchWithContext(context.Background(), params)
+}
+
+// GoogleMapSearchWithContext performs a Google Maps Search with context support
+func (c *Client) GoogleMapSearchWithContext(ctx context.Context, params SerpParams) (GoogleMapsResult, int, error) {
 	params.SearchType = "maps"
-	response, credits, err := c.Serp(params)
+	results, meta, err := searchGoogle[GoogleMapsResult](ctx, c, params, "Google Map")
 	if err != nil {
-		return GoogleMapsResult{}, 0, err
+		return results, 0, err
 	}
+	return results, meta.Credits, nil
+}
 
-	var results GoogleMapsResult
-	err = json.Unmarshal(response, &results)
+// searchGoogle calls the SERP API and decodes the response into T
+func searchGoogle[T any](ctx context.Context, c *Client, params SerpParams, label string) (T, ResponseMeta, error) {
+	var results T
+	response, meta, err := c.SerpWithMeta(ctx, params)
 	if err != nil {
-		return results, 0, fmt.Errorf("failed to parse Google Map results: %w", err)
+		return results, meta, err
 	}
 
-	return results, credits, nil
+	if err := json.Unmarshal(response, &results); err != nil {
+		return results, meta, fmt.Errorf("failed to parse %s results: %w", label, err)
+	}
+	return results, meta, nil
 }
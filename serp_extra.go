@@ -0,0 +1,402 @@
+package ujeebu
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+// decodeWithExtra decodes data into v and returns the fields v does not model
+func decodeWithExtra(data []byte, v any) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return nil, nil
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	collectJSONFields(reflect.TypeOf(v), known)
+	for name := range raw {
+		if known[name] {
+			delete(raw, name)
+		}
+	}
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	return raw, nil
+}
+
+// collectJSONFields adds the JSON field names of struct type t, including embedded structs
+func collectJSONFields(t reflect.Type, known map[string]bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			collectJSONFields(field.Type, known)
+			continue
+		}
+		if name := jsonFieldName(field); name != "" && field.IsExported() {
+			known[name] = true
+		}
+	}
+}
+
+// encodeWithExtra encodes v and adds the extra fields it does not already contain
+func encodeWithExtra(v any, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range extra {
+		if _, ok := fields[name]; !ok {
+			fields[name] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// plainTypes caches the types returned by plainType
+var plainTypes sync.Map
+
+// plainType returns a struct type with the fields of t but without its methods, so that
+// values converted to it are encoded and decoded without calling the methods of t
+func plainType(t reflect.Type) reflect.Type {
+	if plain, ok := plainTypes.Load(t); ok {
+		return plain.(reflect.Type)
+	}
+	fields := make([]reflect.StructField, t.NumField())
+	for i := range fields {
+		fields[i] = t.Field(i)
+	}
+	plain, _ := plainTypes.LoadOrStore(t, reflect.StructOf(fields))
+	return plain.(reflect.Type)
+}
+
+// unmarshalWithExtra decodes data into v and stores the fields v does not model in extra.
+// It is meant to be called from the UnmarshalJSON method of T.
+func unmarshalWithExtra[T any](data []byte, v *T, extra *map[string]json.RawMessage) error {
+	t := reflect.TypeFor[T]()
+	plain := reflect.New(plainType(t))
+	plain.Elem().Set(reflect.ValueOf(v).Elem().Convert(plain.Elem().Type()))
+	fields, err := decodeWithExtra(data, plain.Interface())
+	*v = plain.Elem().Convert(t).Interface().(T)
+	*extra = fields
+	return err
+}
+
+// marshalWithExtra encodes v and adds the extra fields it does not already contain.
+// It is meant to be called from the MarshalJSON method of T.
+func marshalWithExtra[T any](v T, extra map[string]json.RawMessage) ([]byte, error) {
+	plain := reflect.ValueOf(v).Convert(plainType(reflect.TypeFor[T]()))
+	return encodeWithExtra(plain.Interface(), extra)
+}
+
+// scalarJSONString returns the string form of a JSON string, number or boolean
+func scalarJSONString(raw json.RawMessage) (string, bool) {
+	switch string(raw) {
+	case "true", "false":
+		return string(raw), true
+	case "null":
+		return "", false
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, true
+	}
+	var n json.Number
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&n); err == nil {
+		return n.String(), true
+	}
+	return "", false
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping scalar attributes in Attributes
+// and other unknown fields in Extra
+func (k *KnowledgeGraph) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*k = KnowledgeGraph{}
+	fields := map[string]*string{
+		"born":      &k.Born,
+		"died":      &k.Died,
+		"education": &k.Education,
+		"height":    &k.Height,
+		"parents":   &k.Parents,
+		"siblings":  &k.Siblings,
+		"title":     &k.Title,
+		"type":      &k.Type,
+	}
+	for name, value := range raw {
+		if s, ok := scalarJSONString(value); ok {
+			if k.Attributes == nil {
+				k.Attributes = map[string]string{}
+			}
+			k.Attributes[name] = s
+			if field, ok := fields[name]; ok {
+				*field = s
+			}
+			continue
+		}
+		if string(value) == "null" {
+			continue
+		}
+		if k.Extra == nil {
+			k.Extra = map[string]json.RawMessage{}
+		}
+		k.Extra[name] = value
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, including Attributes and Extra
+func (k KnowledgeGraph) MarshalJSON() ([]byte, error) {
+	extra := make(map[string]json.RawMessage, len(k.Attributes)+len(k.Extra))
+	for name, value := range k.Attributes {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		extra[name] = encoded
+	}
+	for name, value := range k.Extra {
+		extra[name] = value
+	}
+	return marshalWithExtra(k, extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown sections in Extra.
+// related_questions is accepted both as a list of strings and as a list of question objects.
+func (r *GoogleSearchResult) UnmarshalJSON(data []byte) error {
+	type wire GoogleSearchResult
+	*r = GoogleSearchResult{}
+	w := struct {
+		*wire
+		RelatedQuestions json.RawMessage `json:"related_questions,omitempty"`
+	}{wire: (*wire)(r)}
+
+	extra, err := decodeWithExtra(data, &w)
+	if err != nil {
+		return err
+	}
+	r.Extra = extra
+
+	if len(w.RelatedQuestions) == 0 || string(w.RelatedQuestions) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(w.RelatedQuestions, &r.RelatedQuestions); err == nil {
+		return nil
+	}
+	var questions []PeopleAlsoAsk
+	if err := json.Unmarshal(w.RelatedQuestions, &questions); err != nil {
+		if r.Extra == nil {
+			r.Extra = map[string]json.RawMessage{}
+		}
+		r.Extra["related_questions"] = w.RelatedQuestions
+		return nil
+	}
+	r.RelatedQuestions = make([]string, 0, len(questions))
+	for _, q := range questions {
+		r.RelatedQuestions = append(r.RelatedQuestions, q.Question)
+	}
+	if len(r.PeopleAlsoAsk) == 0 {
+		r.PeopleAlsoAsk = questions
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, including the sections kept in Extra
+func (r GoogleSearchResult) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(r, r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown sections in Extra
+func (r *GoogleNewsResult) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r, &r.Extra)
+}
+
+// MarshalJSON implements json.Marshaler, including the sections kept in Extra
+func (r GoogleNewsResult) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(r, r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown sections in Extra
+func (r *GoogleVideosResult) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r, &r.Extra)
+}
+
+// MarshalJSON implements json.Marshaler, including the sections kept in Extra
+func (r GoogleVideosResult) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(r, r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown sections in Extra
+func (r *GoogleImagesResult) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r, &r.Extra)
+}
+
+// MarshalJSON implements json.Marshaler, including the sections kept in Extra
+func (r GoogleImagesResult) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(r, r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown sections in Extra
+func (r *GoogleMapsResult) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r, &r.Extra)
+}
+
+// MarshalJSON implements json.Marshaler, including the sections kept in Extra
+func (r GoogleMapsResult) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(r, r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields in Extra
+func (r *OrganicResult) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r, &r.Extra)
+}
+
+// MarshalJSON implements json.Marshaler, including the fields kept in Extra
+func (r OrganicResult) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(r, r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields in Extra
+func (r *SearchVideo) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r, &r.Extra)
+}
+
+// MarshalJSON implements json.Marshaler, including the fields kept in Extra
+func (r SearchVideo) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(r, r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields in Extra
+func (r *TopStory) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r, &r.Extra)
+}
+
+// MarshalJSON implements json.Marshaler, including the fields kept in Extra
+func (r TopStory) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(r, r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields in Extra
+func (r *Ad) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r, &r.Extra)
+}
+
+// MarshalJSON implements json.Marshaler, including the fields kept in Extra
+func (r Ad) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(r, r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields in Extra
+func (r *FeaturedSnippet) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r, &r.Extra)
+}
+
+// MarshalJSON implements json.Marshaler, including the fields kept in Extra
+func (r FeaturedSnippet) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(r, r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields in Extra
+func (r *PeopleAlsoAsk) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r, &r.Extra)
+}
+
+// MarshalJSON implements json.Marshaler, including the fields kept in Extra
+func (r PeopleAlsoAsk) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(r, r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields in Extra
+func (r *LocalResult) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r, &r.Extra)
+}
+
+// MarshalJSON implements json.Marshaler, including the fields kept in Extra
+func (r LocalResult) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(r, r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields in Extra
+func (r *RelatedSearch) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r, &r.Extra)
+}
+
+// MarshalJSON implements json.Marshaler, including the fields kept in Extra
+func (r RelatedSearch) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(r, r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields in Extra
+func (r *News) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r, &r.Extra)
+}
+
+// MarshalJSON implements json.Marshaler, including the fields kept in Extra
+func (r News) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(r, r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields in Extra
+func (r *GoogleVideo) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r, &r.Extra)
+}
+
+// MarshalJSON implements json.Marshaler, including the fields kept in Extra
+func (r GoogleVideo) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(r, r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields in Extra
+func (r *GoogleImage) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r, &r.Extra)
+}
+
+// MarshalJSON implements json.Marshaler, including the fields kept in Extra
+func (r GoogleImage) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(r, r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields in Extra
+func (r *GoogleImageSuggestion) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r, &r.Extra)
+}
+
+// MarshalJSON implements json.Marshaler, including the fields kept in Extra
+func (r GoogleImageSuggestion) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(r, r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields in Extra
+func (r *GoogleMap) UnmarshalJSON(data []byte) error {
+	return unmarshalWithExtra(data, r, &r.Extra)
+}
+
+// MarshalJSON implements json.Marshaler, including the fields kept in Extra
+func (r GoogleMap) MarshalJSON() ([]byte, error) {
+	return marshalWithExtra(r, r.Extra)
+}
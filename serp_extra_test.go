@@ -0,0 +1,118 @@
+package ujeebu
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const richSerpResponse = `{
+	"organic_results": [{"position": 1, "title": "Go", "link": "https://go.dev", "sitelinks": [{"title": "Docs"}]}],
+	"ads": [{"position": 1, "block": "top", "title": "Buy Go", "link": "https://ads.example.com", "tracking": "x"}],
+	"featured_snippet": {"title": "What is Go", "link": "https://go.dev/doc", "description": "Go is a language", "list": ["fast", "simple"]},
+	"people_also_ask": [{"question": "Is Go hard?", "answer": "No"}],
+	"local_results": [{"position": 1, "title": "Gopher Cafe", "address": "1 Main St", "rating": 4.5, "reviews": 120}],
+	"related_searches": [{"query": "golang tutorial", "link": "https://google.com/search?q=golang+tutorial"}],
+	"knowledge_graph": {"title": "Go", "type": "Programming language", "founded": 2009, "open_source": true, "designers": ["Rob Pike"]},
+	"answer_box": {"answer": "42"}
+}`
+
+func TestGoogleSearchResult_RichModel(t *testing.T) {
+	var result GoogleSearchResult
+	require.NoError(t, json.Unmarshal([]byte(richSerpResponse), &result))
+
+	require.Len(t, result.OrganicResults, 1)
+	assert.Equal(t, "https://go.dev", result.OrganicResults[0].Link)
+	assert.JSONEq(t, `[{"title": "Docs"}]`, string(result.OrganicResults[0].Extra["sitelinks"]))
+
+	require.Len(t, result.Ads, 1)
+	assert.Equal(t, "top", result.Ads[0].Block)
+	assert.JSONEq(t, `"x"`, string(result.Ads[0].Extra["tracking"]))
+
+	require.NotNil(t, result.FeaturedSnippet)
+	assert.Equal(t, []string{"fast", "simple"}, result.FeaturedSnippet.List)
+	assert.Nil(t, result.FeaturedSnippet.Extra)
+
+	assert.Equal(t, []PeopleAlsoAsk{{Question: "Is Go hard?", Answer: "No"}}, result.PeopleAlsoAsk)
+	assert.Equal(t, 4.5, result.LocalResults[0].Rating)
+	assert.Equal(t, "golang tutorial", result.RelatedSearches[0].Query)
+
+	kg := result.KnowledgeGraph
+	assert.Equal(t, "Programming language", kg.Type)
+	assert.Equal(t, "2009", kg.Attributes["founded"])
+	assert.Equal(t, "true", kg.Attributes["open_source"])
+	assert.Equal(t, "Go", kg.Attributes["title"])
+	assert.JSONEq(t, `["Rob Pike"]`, string(kg.Extra["designers"]))
+
+	require.Len(t, result.Extra, 1)
+	assert.JSONEq(t, `{"answer": "42"}`, string(result.Extra["answer_box"]))
+}
+
+func TestGoogleSearchResult_RoundTrip(t *testing.T) {
+	var result GoogleSearchResult
+	require.NoError(t, json.Unmarshal([]byte(richSerpResponse), &result))
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	var raw map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.JSONEq(t, `{"answer": "42"}`, string(raw["answer_box"]))
+
+	var decoded GoogleSearchResult
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	again, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(again))
+	assert.Equal(t, result.KnowledgeGraph.Attributes, decoded.KnowledgeGraph.Attributes)
+}
+
+func TestGoogleSearchResult_RelatedQuestions(t *testing.T) {
+	var result GoogleSearchResult
+	require.NoError(t, json.Unmarshal([]byte(`{"related_questions": ["Is Go fast?"]}`), &result))
+	assert.Equal(t, []string{"Is Go fast?"}, result.RelatedQuestions)
+	assert.Empty(t, result.PeopleAlsoAsk)
+
+	result = GoogleSearchResult{}
+	require.NoError(t, json.Unmarshal([]byte(`{"related_questions": [{"question": "Is Go fast?", "answer": "Yes"}]}`), &result))
+	assert.Equal(t, []string{"Is Go fast?"}, result.RelatedQuestions)
+	assert.Equal(t, []PeopleAlsoAsk{{Question: "Is Go fast?", Answer: "Yes"}}, result.PeopleAlsoAsk)
+
+	result = GoogleSearchResult{}
+	require.NoError(t, json.Unmarshal([]byte(`{"related_questions": {"unexpected": true}}`), &result))
+	assert.Empty(t, result.RelatedQuestions)
+	assert.JSONEq(t, `{"unexpected": true}`, string(result.Extra["related_questions"]))
+}
+
+func TestGoogleNewsResult_Extra(t *testing.T) {
+	var result GoogleNewsResult
+	body := `{"news": [{"title": "Go 2", "source": "Blog", "author": "gopher"}], "filters": ["week"], "metadata": {"google_url": "https://google.com"}}`
+	require.NoError(t, json.Unmarshal([]byte(body), &result))
+
+	assert.Equal(t, "https://google.com", result.Metadata.GoogleUrl)
+	assert.JSONEq(t, `"gopher"`, string(result.News[0].Extra["author"]))
+	assert.Equal(t, []string{"filters"}, mapKeys(result.Extra))
+}
+
+func TestGoogleSearch_Extra(t *testing.T) {
+	mockServer, client := setupMockSerpServer(richSerpResponse, map[string]string{CreditsHeader: "25"}, "application/json", http.StatusOK)
+	defer mockServer.Close()
+
+	result, _, err := client.GoogleSearch(SerpParams{Search: "golang"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Extra, "answer_box")
+	assert.Equal(t, []string{
+		FeatureKnowledgeGraph, FeatureAds, FeatureFeaturedSnippet, FeaturePeopleAlsoAsk, FeatureLocalPack,
+	}, serpFeatures(result))
+}
+
+func mapKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
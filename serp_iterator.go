@@ -0,0 +1,163 @@
+package ujeebu
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+// ErrNoMorePages is returned by SerpIterator.Next when the iteration is over
+var ErrNoMorePages = errors.New("ujeebu: no more result pages")
+
+// SerpIteratorOptions bounds a SerpIterator
+type SerpIteratorOptions struct {
+	// MaxPages is the maximum number of pages fetched, 0 for no limit
+	MaxPages int
+	// MaxResults is the maximum number of organic results returned, 0 for no limit
+	MaxResults int
+}
+
+// SerpIterator walks Google Search result pages, starting from SerpParams.Page.
+// The next page is read from Pagination.Api.Next; a page without a next link is the
+// last one. The page number is incremented only when the response has no
+// pagination. Organic results already returned on a previous page are removed from
+// later pages. A SerpIterator is not safe for concurrent use.
+type SerpIterator struct {
+	client  *Client
+	params  SerpParams
+	opts    SerpIteratorOptions
+	pages   int
+	results int
+	credits int
+	seen    map[string]bool
+	done    bool
+}
+
+// NewSerpIterator returns an iterator over the Google Search result pages for params.
+// params.SearchType must be empty or "search": other result types do not have organic results.
+func (c *Client) NewSerpIterator(params SerpParams, opts SerpIteratorOptions) (*SerpIterator, error) {
+	if params.SearchType != "" && params.SearchType != "search" {
+		return nil, &ValidationError{Field: "SearchType", Message: "only Google Search results can be iterated"}
+	}
+	if params.Page <= 0 {
+		params.Page = 1
+	}
+	return &SerpIterator{
+		client: c,
+		params: params,
+		opts:   opts,
+		seen:   map[string]bool{},
+	}, nil
+}
+
+// Credits returns the number of credits spent by the iterator so far
+func (it *SerpIterator) Credits() int {
+	return it.credits
+}
+
+// Next fetches the next result page. It returns ErrNoMorePages once a limit is
+// reached or a page yields no new organic results.
+func (it *SerpIterator) Next(ctx context.Context) (GoogleSearchResult, error) {
+	if it.done || (it.opts.MaxPages > 0 && it.pages >= it.opts.MaxPages) ||
+		(it.opts.MaxResults > 0 && it.results >= it.opts.MaxResults) {
+		it.done = true
+		return GoogleSearchResult{}, ErrNoMorePages
+	}
+
+	page, meta, err := searchGoogle[GoogleSearchResult](ctx, it.client, it.params, "Google Search")
+	it.credits += meta.Credits
+	if err != nil {
+		return GoogleSearchResult{}, err
+	}
+	it.pages++
+
+	organic := make([]OrganicResult, 0, len(page.OrganicResults))
+	for _, result := range page.OrganicResults {
+		key := result.Link
+		if key == "" {
+			key = result.Title
+		}
+		if it.seen[key] {
+			continue
+		}
+		it.seen[key] = true
+		organic = append(organic, result)
+		if it.opts.MaxResults > 0 && it.results+len(organic) >= it.opts.MaxResults {
+			break
+		}
+	}
+	page.OrganicResults = organic
+	it.results += len(organic)
+
+	if len(organic) == 0 {
+		it.done = true
+		return GoogleSearchResult{}, ErrNoMorePages
+	}
+
+	next, ok := nextSerpPage(page.Pagination, it.params.Page)
+	if !ok {
+		it.done = true
+	}
+	it.params.Page = next
+	return page, nil
+}
+
+// Pages returns an iterator over the result pages. Iteration stops after the first error.
+func (it *SerpIterator) Pages(ctx context.Context) iter.Seq2[GoogleSearchResult, error] {
+	return func(yield func(GoogleSearchResult, error) bool) {
+		for {
+			page, err := it.Next(ctx)
+			if errors.Is(err, ErrNoMorePages) {
+				return
+			}
+			if !yield(page, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// OrganicResults returns an iterator over the deduplicated organic results of all pages.
+// Iteration stops after the first error.
+func (it *SerpIterator) OrganicResults(ctx context.Context) iter.Seq2[OrganicResult, error] {
+	return func(yield func(OrganicResult, error) bool) {
+		for page, err := range it.Pages(ctx) {
+			if err != nil {
+				yield(OrganicResult{}, err)
+				return
+			}
+			for _, result := range page.OrganicResults {
+				if !yield(result, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// nextSerpPage returns the page number of the next API link, or current+1 if the response
+// has no pagination. ok is false when the pagination has no next link.
+func nextSerpPage(pagination ResponsePagination, current int) (int, bool) {
+	next := pagination.Api.Next
+	if next == "" {
+		return current + 1, !hasPagination(pagination)
+	}
+	if u, err := url.Parse(next); err == nil {
+		if page, err := strconv.Atoi(u.Query().Get("page")); err == nil && page > current {
+			return page, true
+		}
+	}
+	return current + 1, true
+}
+
+// hasPagination reports whether a response includes any pagination link
+func hasPagination(p ResponsePagination) bool {
+	for _, links := range []PaginationLinks{p.Api, p.Google} {
+		if links.Current != "" || links.Next != "" || len(links.OtherPages) > 0 {
+			return true
+		}
+	}
+	return false
+}
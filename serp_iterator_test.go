@@ -0,0 +1,188 @@
+package ujeebu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupMockSerpPagesServer serves numbered result pages, each with the given organic links
+func setupMockSerpPagesServer(t *testing.T, pages map[int][]string) (*Client, *[]int) {
+	var requested []int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		requested = append(requested, page)
+
+		var organic []OrganicResult
+		for i, link := range pages[page] {
+			organic = append(organic, OrganicResult{Link: link, Position: i + 1, Title: "Result " + link})
+		}
+		body := map[string]any{
+			"organic_results": organic,
+			"pagination": map[string]any{
+				"api": map[string]any{
+					"current":     fmt.Sprintf("https://api.ujeebu.com/serp?page=%d&search=golang", page),
+					"next":        fmt.Sprintf("https://api.ujeebu.com/serp?page=%d&search=golang", page+1),
+					"other_pages": map[string]string{"3": "https://api.ujeebu.com/serp?page=3&search=golang"},
+				},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(CreditsHeader, "25")
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(mockServer.Close)
+
+	client, err := NewClient("test_api_key", WithBaseURL(mockServer.URL))
+	require.NoError(t, err)
+	return client, &requested
+}
+
+func TestSerpIterator_Pages(t *testing.T) {
+	client, requested := setupMockSerpPagesServer(t, map[int][]string{
+		1: {"https://a.com", "https://b.com"},
+		2: {"https://b.com", "https://c.com"},
+		3: {},
+	})
+
+	it, err := client.NewSerpIterator(SerpParams{Search: "golang"}, SerpIteratorOptions{})
+	require.NoError(t, err)
+	var links []string
+	var pages int
+	for page, err := range it.Pages(context.Background()) {
+		require.NoError(t, err)
+		pages++
+		assert.Equal(t, "https://api.ujeebu.com/serp?page=3&search=golang", page.Pagination.Api.OtherPages[3])
+		for _, r := range page.OrganicResults {
+			links = append(links, r.Link)
+		}
+	}
+
+	assert.Equal(t, 2, pages)
+	assert.Equal(t, []string{"https://a.com", "https://b.com", "https://c.com"}, links)
+	assert.Equal(t, []int{1, 2, 3}, *requested)
+	assert.Equal(t, 75, it.Credits())
+
+	_, err = it.Next(context.Background())
+	assert.ErrorIs(t, err, ErrNoMorePages)
+}
+
+func TestSerpIterator_Limits(t *testing.T) {
+	pages := map[int][]string{}
+	for p := 1; p <= 10; p++ {
+		pages[p] = []string{fmt.Sprintf("https://%d-a.com", p), fmt.Sprintf("https://%d-b.com", p)}
+	}
+
+	client, requested := setupMockSerpPagesServer(t, pages)
+	it, err := client.NewSerpIterator(SerpParams{Search: "golang", Page: 2}, SerpIteratorOptions{MaxPages: 3})
+	require.NoError(t, err)
+	var count int
+	for _, err := range it.OrganicResults(context.Background()) {
+		require.NoError(t, err)
+		count++
+	}
+	assert.Equal(t, 6, count)
+	assert.Equal(t, []int{2, 3, 4}, *requested)
+
+	client, requested = setupMockSerpPagesServer(t, pages)
+	it, err = client.NewSerpIterator(SerpParams{Search: "golang"}, SerpIteratorOptions{MaxResults: 3})
+	require.NoError(t, err)
+	var links []string
+	for r, err := range it.OrganicResults(context.Background()) {
+		require.NoError(t, err)
+		links = append(links, r.Link)
+	}
+	assert.Equal(t, []string{"https://1-a.com", "https://1-b.com", "https://2-a.com"}, links)
+	assert.Equal(t, []int{1, 2}, *requested)
+}
+
+func TestSerpIterator_Error(t *testing.T) {
+	mockServer, client := setupMockSerpServer(`{"message": "bad"}`, map[string]string{}, "application/json", http.StatusBadRequest)
+	defer mockServer.Close()
+
+	it, err := client.NewSerpIterator(SerpParams{Search: "golang"}, SerpIteratorOptions{})
+	require.NoError(t, err)
+	var errs []error
+	for _, err := range it.Pages(context.Background()) {
+		errs = append(errs, err)
+	}
+	require.Len(t, errs, 1)
+	var apiErr *APIError
+	assert.ErrorAs(t, errs[0], &apiErr)
+}
+
+func TestSerpIterator_LastPage(t *testing.T) {
+	var requested []int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		requested = append(requested, page)
+		api := map[string]any{"current": fmt.Sprintf("https://api.ujeebu.com/serp?page=%d", page)}
+		if page == 1 {
+			api["next"] = "https://api.ujeebu.com/serp?page=2"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"organic_results": []OrganicResult{{Link: fmt.Sprintf("https://%d.com", page)}},
+			"pagination":      map[string]any{"api": api},
+		})
+	}))
+	defer mockServer.Close()
+
+	client, err := NewClient("test_api_key", WithBaseURL(mockServer.URL))
+	require.NoError(t, err)
+	it, err := client.NewSerpIterator(SerpParams{Search: "golang"}, SerpIteratorOptions{})
+	require.NoError(t, err)
+
+	var links []string
+	for r, err := range it.OrganicResults(context.Background()) {
+		require.NoError(t, err)
+		links = append(links, r.Link)
+	}
+	assert.Equal(t, []string{"https://1.com", "https://2.com"}, links)
+	assert.Equal(t, []int{1, 2}, requested)
+}
+
+func TestSerpIterator_SearchType(t *testing.T) {
+	client := &Client{}
+	_, err := client.NewSerpIterator(SerpParams{Search: "golang", SearchType: "images"}, SerpIteratorOptions{})
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "SearchType", validationErr.Field)
+
+	_, err = client.NewSerpIterator(SerpParams{Search: "golang", SearchType: "search"}, SerpIteratorOptions{})
+	assert.NoError(t, err)
+}
+
+func TestNextSerpPage(t *testing.T) {
+	withNext := func(next string) ResponsePagination {
+		return ResponsePagination{Api: PaginationLinks{Current: "https://api.ujeebu.com/serp?page=2", Next: next}}
+	}
+
+	page, ok := nextSerpPage(withNext("https://api.ujeebu.com/serp?page=5&search=x"), 2)
+	assert.True(t, ok)
+	assert.Equal(t, 5, page)
+
+	page, ok = nextSerpPage(withNext("https://api.ujeebu.com/serp?page=1"), 2)
+	assert.True(t, ok)
+	assert.Equal(t, 3, page)
+
+	page, ok = nextSerpPage(withNext("::not a url"), 2)
+	assert.True(t, ok)
+	assert.Equal(t, 3, page)
+
+	// A pagination block without a next link ends the iteration
+	_, ok = nextSerpPage(withNext(""), 2)
+	assert.False(t, ok)
+
+	// Without pagination the page number is incremented
+	page, ok = nextSerpPage(ResponsePagination{}, 2)
+	assert.True(t, ok)
+	assert.Equal(t, 3, page)
+}
@@ -1,6 +1,7 @@
 package ujeebu
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -563,3 +564,89 @@ func TestGoogleMapSearchResponse(t *testing.T) {
 	assert.Equal(t, "https://api.ujeebu.com/serp?device=desktop&lang=en&location=ca&page=1&results_count=10&search=Italian+restaurant&", result.Pagination.Api.Current)
 	assert.Equal(t, "https://api.ujeebu.com/serp?device=desktop&lang=en&location=ca&page=2&results_count=10&search=Italian+restaurant&", result.Pagination.Api.Next)
 }
+
+func TestGoogleSearchHelpersWithContext(t *testing.T) {
+	var searchTypes []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		searchTypes = append(searchTypes, r.URL.Query().Get("search_type"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(CreditsHeader, "25")
+		_, _ = w.Write([]byte(`{"metadata": {"query_displayed": "golang"}}`))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		apiKey: "test_api_key",
+		client: resty.New().SetBaseURL(mockServer.URL).SetTimeout(10 * time.Second),
+	}
+	ctx := context.Background()
+	params := SerpParams{Search: "golang"}
+
+	search, credits, err := client.GoogleSearchWithContext(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, "golang", search.Metadata.QueryDisplayed)
+	assert.Equal(t, 25, credits)
+
+	_, _, err = client.GoogleImageSearchWithContext(ctx, params)
+	require.NoError(t, err)
+	_, _, err = client.GoogleNewsSearchWithContext(ctx, params)
+	require.NoError(t, err)
+	_, _, err = client.GoogleVideoSearchWithContext(ctx, params)
+	require.NoError(t, err)
+	_, _, err = client.GoogleMapSearchWithContext(ctx, params)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"", "images", "news", "videos", "maps"}, searchTypes)
+}
+
+func TestGoogleSearchHelpersWithContext_Canceled(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		apiKey: "test_api_key",
+		client: resty.New().SetBaseURL(mockServer.URL).SetTimeout(10 * time.Second),
+	}
+	params := SerpParams{Search: "golang"}
+
+	calls := map[string]func(ctx context.Context) error{
+		"search": func(ctx context.Context) error {
+			_, _, err := client.GoogleSearchWithContext(ctx, params)
+			return err
+		},
+		"images": func(ctx context.Context) error {
+			_, _, err := client.GoogleImageSearchWithContext(ctx, params)
+			return err
+		},
+		"news": func(ctx context.Context) error {
+			_, _, err := client.GoogleNewsSearchWithContext(ctx, params)
+			return err
+		},
+		"videos": func(ctx context.Context) error {
+			_, _, err := client.GoogleVideoSearchWithContext(ctx, params)
+			return err
+		},
+		"maps": func(ctx context.Context) error {
+			_, _, err := client.GoogleMapSearchWithContext(ctx, params)
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			err := call(ctx)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			assert.Less(t, time.Since(start), time.Second)
+		})
+	}
+}
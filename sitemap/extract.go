@@ -0,0 +1,20 @@
+package sitemap
+
+import (
+	"context"
+
+	"github.com/ujeebu/ujeebu-go"
+)
+
+// Extract extracts the article of every URL with ExtractWithContext, with the concurrency of opts,
+// and sends the results on the returned channel in completion order. The Index of a result is the
+// position of its URL in urls. params are used for every call with URL set to the page URL.
+// The channel is closed once all URLs are reported and must be drained, as with ujeebu.StreamBatch.
+func Extract(ctx context.Context, extractor ujeebu.Extractor, urls []URL, params ujeebu.ExtractParams, opts ujeebu.BatchOptions) <-chan ujeebu.BatchResult[*ujeebu.Article] {
+	return ujeebu.StreamBatch(ctx, urls, opts, func(ctx context.Context, u URL) (*ujeebu.Article, ujeebu.ResponseMeta, error) {
+		p := params
+		p.URL = u.Loc
+		article, credits, err := extractor.ExtractWithContext(ctx, p)
+		return article, ujeebu.ResponseMeta{Endpoint: "extract", Credits: credits}, err
+	})
+}
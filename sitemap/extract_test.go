@@ -0,0 +1,60 @@
+package sitemap
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ujeebu/ujeebu-go"
+	"github.com/ujeebu/ujeebu-go/ujeebutest"
+)
+
+func TestExtract(t *testing.T) {
+	server := ujeebutest.NewServer(t)
+	server.Fail("extract", ujeebutest.Fault{StatusCode: http.StatusNotFound, Message: "not found", Times: 1})
+	urls := []URL{
+		{Loc: "https://news.com/posts/1"},
+		{Loc: "https://news.com/posts/2"},
+		{Loc: "https://news.com/posts/3"},
+	}
+
+	var progress []ujeebu.BatchProgress
+	articles := map[int]*ujeebu.Article{}
+	failed := 0
+	for res := range Extract(context.Background(), server.Client(), urls, ujeebu.ExtractParams{JS: true, Images: true}, ujeebu.BatchOptions{
+		Concurrency: 1,
+		OnProgress:  func(p ujeebu.BatchProgress) { progress = append(progress, p) },
+	}) {
+		if res.Err != nil {
+			failed++
+			continue
+		}
+		articles[res.Index] = res.Value
+	}
+
+	assert.Equal(t, 1, failed)
+	require.Len(t, articles, 2)
+	for i, article := range articles {
+		assert.Equal(t, urls[i].Loc, article.URL)
+	}
+	require.Len(t, progress, 3)
+	assert.Equal(t, ujeebu.BatchProgress{Completed: 3, Total: 3, Failed: 1, Credits: 2 * ujeebutest.DefaultCredits["extract"]}, progress[2])
+	server.AssertParam(t, "extract", "js", "true")
+	server.AssertParam(t, "extract", "images", "true")
+}
+
+func TestExtract_Mock(t *testing.T) {
+	mock := &ujeebutest.MockAPI{}
+	var results []ujeebu.BatchResult[*ujeebu.Article]
+	for res := range Extract(context.Background(), mock, []URL{{Loc: "https://news.com/a"}}, ujeebu.ExtractParams{}, ujeebu.BatchOptions{}) {
+		results = append(results, res)
+	}
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+
+	calls := mock.CallsTo("ExtractWithContext")
+	require.Len(t, calls, 1)
+	assert.Equal(t, "https://news.com/a", calls[0].Params.(ujeebu.ExtractParams).URL)
+}
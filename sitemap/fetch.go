@@ -0,0 +1,192 @@
+package sitemap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ujeebu/ujeebu-go"
+	"github.com/ujeebu/ujeebu-go/robots"
+)
+
+// DefaultMaxSitemaps is the maximum number of sitemap files fetched by a walk when Options.MaxSitemaps is not set
+const DefaultMaxSitemaps = 100
+
+// Options configures a Fetcher
+type Options struct {
+	// Params are the scrape parameters of sitemap and robots.txt requests (proxy, session, headers...).
+	// URL is set to the sitemap URL and files are fetched in raw mode without JS rendering, so
+	// ResponseType, JS, JSONOutput and ExtractRules are ignored.
+	Params ujeebu.ScrapeParams
+	// Robots is the robots.txt cache used for discovery, created from Params if nil
+	Robots *robots.Cache
+	// Since keeps the URLs modified at or after this time. URLs without date are dropped when it is set.
+	Since time.Time
+	// Until keeps the URLs modified before this time. URLs without date are dropped when it is set.
+	Until time.Time
+	// MaxSitemaps is the maximum number of sitemap files fetched by a walk (DefaultMaxSitemaps if zero)
+	MaxSitemaps int
+	// MaxURLs is the maximum number of URLs returned by a walk (unlimited if zero)
+	MaxURLs int
+	// Concurrency is the number of sitemaps fetched in parallel (ujeebu.DefaultBatchConcurrency if zero)
+	Concurrency int
+}
+
+// Fetcher discovers and fetches sitemaps through the Scrape API. It is safe for concurrent use.
+type Fetcher struct {
+	scraper ujeebu.Scraper
+	opts    Options
+}
+
+// New creates a Fetcher fetching sitemaps with scraper, usually a *ujeebu.Client
+func New(scraper ujeebu.Scraper, opts Options) (*Fetcher, error) {
+	if scraper == nil {
+		return nil, &ujeebu.ValidationError{Field: "scraper", Message: "scraper is required"}
+	}
+	if opts.MaxSitemaps < 0 || opts.MaxURLs < 0 || opts.Concurrency < 0 {
+		return nil, &ujeebu.ValidationError{Field: "Options", Message: "MaxSitemaps, MaxURLs and Concurrency must not be negative"}
+	}
+	if opts.MaxSitemaps == 0 {
+		opts.MaxSitemaps = DefaultMaxSitemaps
+	}
+	if opts.Robots == nil {
+		cache, err := robots.NewCache(scraper, robots.Options{Params: opts.Params})
+		if err != nil {
+			return nil, err
+		}
+		opts.Robots = cache
+	}
+	return &Fetcher{scraper: scraper, opts: opts}, nil
+}
+
+// Discover returns the sitemap URLs of the site of siteURL: the Sitemap lines of its
+// robots.txt, or its /sitemap.xml if there are none or robots.txt cannot be fetched
+func (f *Fetcher) Discover(ctx context.Context, siteURL string) ([]string, error) {
+	u, err := url.Parse(siteURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, &ujeebu.ValidationError{Field: "URL", Message: fmt.Sprintf("%q is not an absolute http or https URL", siteURL)}
+	}
+	if r, err := f.opts.Robots.Get(ctx, siteURL); err == nil && len(r.Sitemaps) > 0 {
+		return r.Sitemaps, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return []string{originOf(u) + "/sitemap.xml"}, nil
+}
+
+// Fetch fetches and parses a single sitemap file
+func (f *Fetcher) Fetch(ctx context.Context, sitemapURL string) (*Sitemap, error) {
+	sm, _, err := f.fetch(ctx, sitemapURL)
+	return sm, err
+}
+
+func (f *Fetcher) fetch(ctx context.Context, sitemapURL string) (*Sitemap, ujeebu.ResponseMeta, error) {
+	params := f.opts.Params
+	params.URL = sitemapURL
+	params.ResponseType = "raw"
+	params.JS = false
+	params.JSONOutput = false
+	params.ExtractRules = nil
+
+	res, meta, err := f.scraper.ScrapeRawWithMeta(ctx, params)
+	if err != nil {
+		return nil, meta, fmt.Errorf("sitemap: fetching %s: %w", sitemapURL, err)
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, meta, fmt.Errorf("sitemap: fetching %s: unexpected status %d", sitemapURL, res.StatusCode)
+	}
+	sm, err := Parse(res.Body)
+	if err != nil {
+		return nil, meta, fmt.Errorf("%w (%s)", err, sitemapURL)
+	}
+	for i := range sm.URLs {
+		sm.URLs[i].Sitemap = sitemapURL
+	}
+	return sm, meta, nil
+}
+
+// URLs fetches the sitemaps, following sitemap indexes, and returns the URLs they list without
+// duplicates and filtered by Since and Until. Sitemaps of an index modified before Since are not
+// fetched. The sitemaps that fail are skipped, and their errors are joined in the returned error
+// alongside the URLs of the others.
+func (f *Fetcher) URLs(ctx context.Context, sitemapURLs ...string) ([]URL, error) {
+	var urls []URL
+	var errs []error
+	seenSitemaps := map[string]bool{}
+	seenURLs := map[string]bool{}
+	fetched := 0
+
+	var level []string
+	for _, loc := range sitemapURLs {
+		if !seenSitemaps[loc] {
+			seenSitemaps[loc] = true
+			level = append(level, loc)
+		}
+	}
+	for len(level) > 0 {
+		if remaining := f.opts.MaxSitemaps - fetched; len(level) > remaining {
+			level = level[:remaining]
+		}
+		fetched += len(level)
+
+		var next []string
+		report := ujeebu.RunBatch(ctx, level, ujeebu.BatchOptions{Concurrency: f.opts.Concurrency}, f.fetch)
+		for _, res := range report.Results {
+			if res.Err != nil {
+				errs = append(errs, res.Err)
+				continue
+			}
+			for _, u := range res.Value.URLs {
+				if seenURLs[u.Loc] || !f.keep(u.Modified()) {
+					continue
+				}
+				seenURLs[u.Loc] = true
+				urls = append(urls, u)
+				if f.opts.MaxURLs > 0 && len(urls) == f.opts.MaxURLs {
+					return urls, errors.Join(errs...)
+				}
+			}
+			for _, e := range res.Value.Sitemaps {
+				if seenSitemaps[e.Loc] || (!f.opts.Since.IsZero() && !e.LastMod.IsZero() && e.LastMod.Before(f.opts.Since)) {
+					continue
+				}
+				seenSitemaps[e.Loc] = true
+				next = append(next, e.Loc)
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return urls, err
+		}
+		level = next
+	}
+	return urls, errors.Join(errs...)
+}
+
+// SiteURLs discovers the sitemaps of the site of siteURL and returns the URLs they list, as URLs does
+func (f *Fetcher) SiteURLs(ctx context.Context, siteURL string) ([]URL, error) {
+	sitemaps, err := f.Discover(ctx, siteURL)
+	if err != nil {
+		return nil, err
+	}
+	return f.URLs(ctx, sitemaps...)
+}
+
+// keep reports whether a URL modified at t passes the Since and Until filters
+func (f *Fetcher) keep(t time.Time) bool {
+	if f.opts.Since.IsZero() && f.opts.Until.IsZero() {
+		return true
+	}
+	if t.IsZero() {
+		return false
+	}
+	return (f.opts.Since.IsZero() || !t.Before(f.opts.Since)) && (f.opts.Until.IsZero() || t.Before(f.opts.Until))
+}
+
+// originOf returns the scheme and host of u
+func originOf(u *url.URL) string {
+	return strings.ToLower(u.Scheme) + "://" + u.Host
+}
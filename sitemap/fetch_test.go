@@ -0,0 +1,163 @@
+package sitemap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ujeebu/ujeebu-go"
+	"github.com/ujeebu/ujeebu-go/ujeebutest"
+)
+
+func urlsetOf(entries ...string) string {
+	body := `<?xml version="1.0" encoding="UTF-8"?><urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`
+	for i := 0; i < len(entries); i += 2 {
+		body += fmt.Sprintf("<url><loc>%s</loc><lastmod>%s</lastmod></url>", entries[i], entries[i+1])
+	}
+	return body + "</urlset>"
+}
+
+// newSitemapServer serves the robots.txt and sitemaps of news.com and plain.com from the scrape endpoint
+func newSitemapServer(t *testing.T) *ujeebutest.Server {
+	files := map[string][]byte{
+		"https://news.com/robots.txt": []byte("User-agent: *\nDisallow: /admin\n\nSitemap: https://news.com/sitemap_index.xml\nSitemap: https://news.com/news.xml\n"),
+		"https://news.com/sitemap_index.xml": []byte(`<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+			<sitemap><loc>https://news.com/posts.xml.gz</loc><lastmod>2024-03-02</lastmod></sitemap>
+			<sitemap><loc>https://news.com/archive.xml</loc><lastmod>2020-01-01</lastmod></sitemap>
+			<sitemap><loc>https://news.com/missing.xml</loc></sitemap>
+			<sitemap><loc>https://news.com/news.xml</loc></sitemap>
+		</sitemapindex>`),
+		"https://news.com/posts.xml.gz": gzipped(t, urlsetOf(
+			"https://news.com/posts/1", "2024-03-01",
+			"https://news.com/posts/2", "2024-02-01",
+			"https://news.com/breaking", "2024-03-02",
+		)),
+		"https://news.com/archive.xml": []byte(urlsetOf("https://news.com/posts/0", "2019-12-31")),
+		"https://news.com/news.xml": []byte(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:news="http://www.google.com/schemas/sitemap-news/0.9">
+			<url><loc>https://news.com/breaking</loc><news:news><news:publication_date>2024-03-02T09:00:00Z</news:publication_date><news:title>Breaking</news:title></news:news></url>
+		</urlset>`),
+		"https://plain.com/sitemap.xml": []byte(urlsetOf("https://plain.com/", "2024-01-01")),
+	}
+	server := ujeebutest.NewServer(t)
+	server.Handle("scrape", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := files[r.URL.Query().Get("url")]
+		if !ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "page not found"}`))
+			return
+		}
+		_, _ = w.Write(body)
+	})
+	return server
+}
+
+func locs(urls []URL) []string {
+	var res []string
+	for _, u := range urls {
+		res = append(res, u.Loc)
+	}
+	return res
+}
+
+func TestFetcher_Discover(t *testing.T) {
+	server := newSitemapServer(t)
+	fetcher, err := New(server.Client(), Options{})
+	require.NoError(t, err)
+
+	sitemaps, err := fetcher.Discover(context.Background(), "https://news.com/section/page")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://news.com/sitemap_index.xml", "https://news.com/news.xml"}, sitemaps)
+
+	sitemaps, err = fetcher.Discover(context.Background(), "https://plain.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://plain.com/sitemap.xml"}, sitemaps)
+
+	var validationErr *ujeebu.ValidationError
+	_, err = fetcher.Discover(context.Background(), "plain.com")
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestFetcher_SiteURLs(t *testing.T) {
+	server := newSitemapServer(t)
+	fetcher, err := New(server.Client(), Options{Params: ujeebu.ScrapeParams{ProxyType: "residential", JS: true}})
+	require.NoError(t, err)
+
+	urls, err := fetcher.SiteURLs(context.Background(), "https://news.com")
+	assert.ErrorContains(t, err, "https://news.com/missing.xml")
+	assert.Equal(t, []string{
+		"https://news.com/breaking",
+		"https://news.com/posts/1",
+		"https://news.com/posts/2",
+		"https://news.com/posts/0",
+	}, locs(urls))
+	assert.Equal(t, "https://news.com/news.xml", urls[0].Sitemap)
+	assert.Equal(t, "Breaking", urls[0].News.Title)
+	assert.Equal(t, "https://news.com/posts.xml.gz", urls[1].Sitemap)
+
+	// Sitemaps are fetched in raw mode with the scrape settings
+	req, ok := server.LastRequest("scrape")
+	require.True(t, ok)
+	assert.Equal(t, "raw", req.Param("response_type"))
+	assert.Equal(t, "false", req.Param("js"))
+	assert.Equal(t, "residential", req.Param("proxy_type"))
+}
+
+func TestFetcher_LastModFilter(t *testing.T) {
+	server := newSitemapServer(t)
+	fetcher, err := New(server.Client(), Options{
+		Since: time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	urls, err := fetcher.URLs(context.Background(), "https://news.com/sitemap_index.xml")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"https://news.com/posts/1"}, locs(urls))
+
+	// Sitemaps last modified before Since are not fetched
+	for _, req := range server.Requests("scrape") {
+		assert.NotEqual(t, "https://news.com/archive.xml", req.Param("url"))
+	}
+}
+
+func TestFetcher_Limits(t *testing.T) {
+	server := newSitemapServer(t)
+	fetcher, err := New(server.Client(), Options{MaxURLs: 2})
+	require.NoError(t, err)
+	urls, err := fetcher.URLs(context.Background(), "https://news.com/news.xml", "https://news.com/posts.xml.gz")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://news.com/breaking", "https://news.com/posts/1"}, locs(urls))
+
+	server = newSitemapServer(t)
+	fetcher, err = New(server.Client(), Options{MaxSitemaps: 2})
+	require.NoError(t, err)
+	urls, err = fetcher.URLs(context.Background(), "https://news.com/sitemap_index.xml")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://news.com/posts/1", "https://news.com/posts/2", "https://news.com/breaking"}, locs(urls))
+	server.AssertCalls(t, "scrape", 2)
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	server := newSitemapServer(t)
+	fetcher, err := New(server.Client(), Options{})
+	require.NoError(t, err)
+
+	sm, err := fetcher.Fetch(context.Background(), "https://news.com/sitemap_index.xml")
+	require.NoError(t, err)
+	assert.Len(t, sm.Sitemaps, 4)
+
+	_, err = fetcher.Fetch(context.Background(), "https://news.com/missing.xml")
+	var apiErr *ujeebu.APIError
+	assert.ErrorAs(t, err, &apiErr)
+
+	var validationErr *ujeebu.ValidationError
+	_, err = New(nil, Options{})
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = New(server.Client(), Options{MaxURLs: -1})
+	assert.ErrorAs(t, err, &validationErr)
+}
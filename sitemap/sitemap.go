@@ -0,0 +1,248 @@
+// Package sitemap discovers, fetches and parses sitemaps, and extracts the
+// articles they list through the Extract API.
+//
+// Sitemaps are found in the Sitemap lines of robots.txt, or at /sitemap.xml,
+// and fetched through the Scrape API in raw mode:
+//
+//	fetcher, err := sitemap.New(client, sitemap.Options{Since: time.Now().AddDate(0, 0, -7)})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	urls, err := fetcher.SiteURLs(ctx, "https://example.com")
+//	for res := range sitemap.Extract(ctx, client, urls, ujeebu.ExtractParams{}, ujeebu.BatchOptions{Concurrency: 4}) {
+//		...
+//	}
+package sitemap
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxSize is the maximum size of an uncompressed sitemap, as set by the sitemap protocol
+const MaxSize = 50 << 20
+
+// ErrTooLarge is returned for sitemaps larger than MaxSize once uncompressed
+var ErrTooLarge = errors.New("sitemap: sitemap larger than 50MB")
+
+// URL is a page listed in a urlset
+type URL struct {
+	// Loc is the URL of the page
+	Loc string
+	// LastMod is the last modification time of the page, zero if not set
+	LastMod time.Time
+	// ChangeFreq is how often the page changes (always, hourly, daily...), empty if not set
+	ChangeFreq string
+	// Priority is the priority of the page between 0 and 1, zero if not set
+	Priority float64
+	// News is the Google News extension of the page, nil if not set
+	News *News
+	// Images are the Google Image extension entries of the page
+	Images []Image
+	// Sitemap is the URL of the sitemap listing the page, set by Fetcher
+	Sitemap string
+}
+
+// Modified returns LastMod, or the news publication date if LastMod is not set
+func (u URL) Modified() time.Time {
+	if u.LastMod.IsZero() && u.News != nil {
+		return u.News.PublicationDate
+	}
+	return u.LastMod
+}
+
+// News is the Google News extension of a URL
+type News struct {
+	PublicationName     string
+	PublicationLanguage string
+	PublicationDate     time.Time
+	Title               string
+	// Keywords are the comma-separated keywords of the article
+	Keywords string
+}
+
+// Image is a Google Image extension entry of a URL
+type Image struct {
+	Loc     string
+	Caption string
+	Title   string
+}
+
+// Entry is a sitemap listed in a sitemap index
+type Entry struct {
+	// Loc is the URL of the sitemap
+	Loc string
+	// LastMod is the last modification time of the sitemap, zero if not set
+	LastMod time.Time
+}
+
+// Sitemap is a parsed sitemap file: a urlset listing pages, or a sitemap index listing other sitemaps
+type Sitemap struct {
+	// URLs are the pages of a urlset or text sitemap
+	URLs []URL
+	// Sitemaps are the entries of a sitemap index
+	Sitemaps []Entry
+}
+
+// IsIndex reports whether the sitemap is a sitemap index
+func (s *Sitemap) IsIndex() bool {
+	return len(s.Sitemaps) > 0
+}
+
+// XML documents. Elements are matched by local name, except the news and image
+// extensions which are matched by namespace as well.
+type (
+	xmlDocument struct {
+		XMLName  xml.Name
+		URLs     []xmlURL   `xml:"url"`
+		Sitemaps []xmlEntry `xml:"sitemap"`
+	}
+	xmlEntry struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	}
+	xmlURL struct {
+		Loc        string     `xml:"loc"`
+		LastMod    string     `xml:"lastmod"`
+		ChangeFreq string     `xml:"changefreq"`
+		Priority   string     `xml:"priority"`
+		News       *xmlNews   `xml:"http://www.google.com/schemas/sitemap-news/0.9 news"`
+		Images     []xmlImage `xml:"http://www.google.com/schemas/sitemap-image/1.1 image"`
+	}
+	xmlNews struct {
+		Name            string `xml:"publication>name"`
+		Language        string `xml:"publication>language"`
+		PublicationDate string `xml:"publication_date"`
+		Title           string `xml:"title"`
+		Keywords        string `xml:"keywords"`
+	}
+	xmlImage struct {
+		Loc     string `xml:"loc"`
+		Caption string `xml:"caption"`
+		Title   string `xml:"title"`
+	}
+)
+
+// Parse parses a sitemap: an XML urlset or sitemap index, or a text file with one URL per line.
+// Gzip-compressed sitemaps are decompressed. Entries without an absolute http or https URL are ignored.
+func Parse(data []byte) (*Sitemap, error) {
+	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("sitemap: %w", err)
+		}
+		defer zr.Close()
+		if data, err = io.ReadAll(io.LimitReader(zr, MaxSize+1)); err != nil {
+			return nil, fmt.Errorf("sitemap: %w", err)
+		}
+	}
+	if len(data) > MaxSize {
+		return nil, ErrTooLarge
+	}
+
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] != '<' {
+		return parseText(trimmed), nil
+	}
+
+	var doc xmlDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("sitemap: %w", err)
+	}
+	sm := &Sitemap{}
+	switch doc.XMLName.Local {
+	case "urlset":
+		for _, u := range doc.URLs {
+			if loc, ok := absoluteURL(u.Loc); ok {
+				sm.URLs = append(sm.URLs, u.toURL(loc))
+			}
+		}
+	case "sitemapindex":
+		for _, e := range doc.Sitemaps {
+			if loc, ok := absoluteURL(e.Loc); ok {
+				sm.Sitemaps = append(sm.Sitemaps, Entry{Loc: loc, LastMod: parseTime(e.LastMod)})
+			}
+		}
+	default:
+		return nil, fmt.Errorf("sitemap: unexpected root element <%s>", doc.XMLName.Local)
+	}
+	return sm, nil
+}
+
+func (u xmlURL) toURL(loc string) URL {
+	res := URL{
+		Loc:        loc,
+		LastMod:    parseTime(u.LastMod),
+		ChangeFreq: strings.ToLower(strings.TrimSpace(u.ChangeFreq)),
+	}
+	if p, err := strconv.ParseFloat(strings.TrimSpace(u.Priority), 64); err == nil {
+		res.Priority = p
+	}
+	if u.News != nil {
+		res.News = &News{
+			PublicationName:     strings.TrimSpace(u.News.Name),
+			PublicationLanguage: strings.TrimSpace(u.News.Language),
+			PublicationDate:     parseTime(u.News.PublicationDate),
+			Title:               strings.TrimSpace(u.News.Title),
+			Keywords:            strings.TrimSpace(u.News.Keywords),
+		}
+	}
+	for _, img := range u.Images {
+		if imgLoc, ok := absoluteURL(img.Loc); ok {
+			res.Images = append(res.Images, Image{Loc: imgLoc, Caption: strings.TrimSpace(img.Caption), Title: strings.TrimSpace(img.Title)})
+		}
+	}
+	return res
+}
+
+// parseText parses a text sitemap
+func parseText(data []byte) *Sitemap {
+	sm := &Sitemap{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if loc, ok := absoluteURL(scanner.Text()); ok {
+			sm.URLs = append(sm.URLs, URL{Loc: loc})
+		}
+	}
+	return sm
+}
+
+// absoluteURL returns the trimmed loc if it is an absolute http or https URL
+func absoluteURL(loc string) (string, bool) {
+	loc = strings.TrimSpace(loc)
+	u, err := url.Parse(loc)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", false
+	}
+	return loc, true
+}
+
+// timeLayouts are the W3C datetime formats used by sitemaps
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// parseTime parses a W3C datetime, returning the zero time if it is invalid
+func parseTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,142 @@
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const urlset = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"
+        xmlns:news="http://www.google.com/schemas/sitemap-news/0.9"
+        xmlns:image="http://www.google.com/schemas/sitemap-image/1.1">
+  <url>
+    <loc> https://example.com/news/launch </loc>
+    <news:news>
+      <news:publication>
+        <news:name>Example Times</news:name>
+        <news:language>en</news:language>
+      </news:publication>
+      <news:publication_date>2024-03-01T08:30:00+01:00</news:publication_date>
+      <news:title>Launch day</news:title>
+      <news:keywords>launch, product</news:keywords>
+    </news:news>
+    <image:image>
+      <image:loc>https://example.com/img/launch.jpg</image:loc>
+      <image:caption>The launch</image:caption>
+    </image:image>
+    <image:image>
+      <image:loc>https://example.com/img/team.jpg</image:loc>
+    </image:image>
+  </url>
+  <url>
+    <loc>https://example.com/about</loc>
+    <lastmod>2023-12-24</lastmod>
+    <changefreq>Monthly</changefreq>
+    <priority>0.8</priority>
+  </url>
+  <url>
+    <loc>/relative</loc>
+  </url>
+</urlset>`
+
+const index = `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap>
+    <loc>https://example.com/sitemap-posts.xml.gz</loc>
+    <lastmod>2024-03-01T10:00:00Z</lastmod>
+  </sitemap>
+  <sitemap>
+    <loc>https://example.com/sitemap-pages.xml</loc>
+  </sitemap>
+</sitemapindex>`
+
+func TestParse_URLSet(t *testing.T) {
+	sm, err := Parse([]byte(urlset))
+	require.NoError(t, err)
+	assert.False(t, sm.IsIndex())
+	require.Len(t, sm.URLs, 2)
+
+	news := sm.URLs[0]
+	assert.Equal(t, "https://example.com/news/launch", news.Loc)
+	assert.True(t, news.LastMod.IsZero())
+	require.NotNil(t, news.News)
+	assert.Equal(t, "Example Times", news.News.PublicationName)
+	assert.Equal(t, "en", news.News.PublicationLanguage)
+	assert.Equal(t, "Launch day", news.News.Title)
+	assert.Equal(t, "launch, product", news.News.Keywords)
+	assert.True(t, news.News.PublicationDate.Equal(time.Date(2024, 3, 1, 7, 30, 0, 0, time.UTC)))
+	assert.Equal(t, news.News.PublicationDate, news.Modified())
+	assert.Equal(t, []Image{
+		{Loc: "https://example.com/img/launch.jpg", Caption: "The launch"},
+		{Loc: "https://example.com/img/team.jpg"},
+	}, news.Images)
+
+	about := sm.URLs[1]
+	assert.Equal(t, time.Date(2023, 12, 24, 0, 0, 0, 0, time.UTC), about.LastMod)
+	assert.Equal(t, about.LastMod, about.Modified())
+	assert.Equal(t, "monthly", about.ChangeFreq)
+	assert.Equal(t, 0.8, about.Priority)
+	assert.Nil(t, about.News)
+}
+
+func TestParse_Index(t *testing.T) {
+	sm, err := Parse([]byte(index))
+	require.NoError(t, err)
+	assert.True(t, sm.IsIndex())
+	assert.Equal(t, []Entry{
+		{Loc: "https://example.com/sitemap-posts.xml.gz", LastMod: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)},
+		{Loc: "https://example.com/sitemap-pages.xml"},
+	}, sm.Sitemaps)
+}
+
+func TestParse_Gzip(t *testing.T) {
+	sm, err := Parse(gzipped(t, urlset))
+	require.NoError(t, err)
+	assert.Len(t, sm.URLs, 2)
+
+	_, err = Parse([]byte{0x1f, 0x8b, 0x00})
+	assert.Error(t, err)
+}
+
+func TestParse_Text(t *testing.T) {
+	sm, err := Parse([]byte("\xef\xbb\xbfhttps://example.com/a\r\n\nnot a url\nhttps://example.com/b\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []URL{{Loc: "https://example.com/a"}, {Loc: "https://example.com/b"}}, sm.URLs)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	_, err := Parse([]byte("<html><body>Not found</body></html>"))
+	assert.ErrorContains(t, err, "unexpected root element <html>")
+
+	_, err = Parse([]byte("<urlset><url>"))
+	assert.Error(t, err)
+}
+
+func TestParseTime(t *testing.T) {
+	tests := map[string]time.Time{
+		"2024-03-01T08:30:15.5Z": time.Date(2024, 3, 1, 8, 30, 15, 500000000, time.UTC),
+		"2024-03-01T08:30+00:00": time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC),
+		"2024-03-01T08:30:15":    time.Date(2024, 3, 1, 8, 30, 15, 0, time.UTC),
+		" 2024-03 ":              time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		"2024":                   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		"yesterday":              {},
+		"":                       {},
+	}
+	for in, want := range tests {
+		assert.True(t, want.Equal(parseTime(in)), in)
+	}
+}
+
+func gzipped(t *testing.T, s string) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(s))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
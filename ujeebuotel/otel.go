@@ -0,0 +1,229 @@
+// Package ujeebuotel instruments the Ujeebu client with OpenTelemetry traces and metrics.
+//
+// It lives in its own module so that the core SDK does not depend on OpenTelemetry.
+package ujeebuotel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/ujeebu/ujeebu-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the tracer and meter
+const ScopeName = "github.com/ujeebu/ujeebu-go/ujeebuotel"
+
+// Attribute keys set on spans and metrics
+const (
+	EndpointKey   = attribute.Key("ujeebu.endpoint")
+	TargetHostKey = attribute.Key("ujeebu.target.host")
+	ProxyTypeKey  = attribute.Key("ujeebu.proxy_type")
+	JSKey         = attribute.Key("ujeebu.js")
+	CreditsKey    = attribute.Key("ujeebu.credits")
+	RetriesKey    = attribute.Key("ujeebu.retries")
+	LatencyKey    = attribute.Key("ujeebu.latency_ms")
+	CachedKey     = attribute.Key("ujeebu.cached")
+	RequestIDKey  = attribute.Key("ujeebu.request_id")
+	StatusCodeKey = attribute.Key("http.response.status_code")
+	// ErrorTypeKey is the APIError code, or the error class for other errors
+	ErrorTypeKey = attribute.Key("error.type")
+)
+
+// Metric instrument names
+const (
+	RequestsMetric = "ujeebu.client.requests"
+	ErrorsMetric   = "ujeebu.client.errors"
+	CreditsMetric  = "ujeebu.client.credits"
+	DurationMetric = "ujeebu.client.duration"
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option configures the instrumentation
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider (the global one by default)
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider sets the meter provider (the global one by default)
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+type instruments struct {
+	tracer   trace.Tracer
+	requests metric.Int64Counter
+	errors   metric.Int64Counter
+	credits  metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+// ClientOption returns a client option installing the instrumentation middleware
+func ClientOption(opts ...Option) ujeebu.ClientOption {
+	return ujeebu.WithMiddleware(Middleware(opts...))
+}
+
+// Middleware returns a client middleware emitting a span and metrics for every API call.
+// Instrument creation errors are reported to otel.Handle and the failing instrument is disabled.
+func Middleware(opts ...Option) ujeebu.Middleware {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(ScopeName)
+	fallback := noop.NewMeterProvider().Meter(ScopeName)
+	inst := instruments{tracer: cfg.tracerProvider.Tracer(ScopeName)}
+
+	var err error
+	if inst.requests, err = meter.Int64Counter(RequestsMetric,
+		metric.WithDescription("Number of API calls"),
+		metric.WithUnit("{request}")); err != nil {
+		otel.Handle(err)
+		inst.requests, _ = fallback.Int64Counter(RequestsMetric)
+	}
+	if inst.errors, err = meter.Int64Counter(ErrorsMetric,
+		metric.WithDescription("Number of failed API calls"),
+		metric.WithUnit("{error}")); err != nil {
+		otel.Handle(err)
+		inst.errors, _ = fallback.Int64Counter(ErrorsMetric)
+	}
+	if inst.credits, err = meter.Int64Counter(CreditsMetric,
+		metric.WithDescription("Credits consumed by API calls"),
+		metric.WithUnit("{credit}")); err != nil {
+		otel.Handle(err)
+		inst.credits, _ = fallback.Int64Counter(CreditsMetric)
+	}
+	if inst.duration, err = meter.Float64Histogram(DurationMetric,
+		metric.WithDescription("Duration of API calls including retries"),
+		metric.WithUnit("s")); err != nil {
+		otel.Handle(err)
+		inst.duration, _ = fallback.Float64Histogram(DurationMetric)
+	}
+
+	return func(next ujeebu.Handler) ujeebu.Handler {
+		return func(ctx context.Context, call *ujeebu.Call) (any, ujeebu.ResponseMeta, error) {
+			return inst.handle(ctx, call, next)
+		}
+	}
+}
+
+func (inst *instruments) handle(ctx context.Context, call *ujeebu.Call, next ujeebu.Handler) (any, ujeebu.ResponseMeta, error) {
+	ctx, span := inst.tracer.Start(ctx, "ujeebu."+call.Endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(requestAttributes(call)...),
+	)
+	defer span.End()
+
+	res, meta, err := next(ctx, call)
+
+	endpoint := EndpointKey.String(call.Endpoint)
+	span.SetAttributes(
+		CreditsKey.Int(meta.Credits),
+		RetriesKey.Int(retries(meta)),
+		LatencyKey.Int64(meta.Duration.Milliseconds()),
+		CachedKey.Bool(meta.Cached),
+	)
+	if meta.StatusCode != 0 {
+		span.SetAttributes(StatusCodeKey.Int(meta.StatusCode))
+	}
+	if meta.RequestID != "" {
+		span.SetAttributes(RequestIDKey.String(meta.RequestID))
+	}
+
+	attrs := []attribute.KeyValue{endpoint, CachedKey.Bool(meta.Cached)}
+	if meta.StatusCode != 0 {
+		attrs = append(attrs, StatusCodeKey.Int(meta.StatusCode))
+	}
+	inst.requests.Add(ctx, 1, metric.WithAttributes(attrs...))
+	inst.duration.Record(ctx, meta.Duration.Seconds(), metric.WithAttributes(attrs...))
+	if meta.Credits > 0 {
+		inst.credits.Add(ctx, int64(meta.Credits), metric.WithAttributes(endpoint))
+	}
+
+	if err != nil {
+		errType := ErrorTypeKey.String(errorType(err))
+		span.SetAttributes(errType)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		inst.errors.Add(ctx, 1, metric.WithAttributes(endpoint, errType))
+	}
+	return res, meta, err
+}
+
+// requestAttributes describes the call parameters
+func requestAttributes(call *ujeebu.Call) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{EndpointKey.String(call.Endpoint)}
+	var target, proxyType string
+	var js bool
+	switch p := call.Params.(type) {
+	case ujeebu.ExtractParams:
+		target, proxyType, js = p.URL, p.ProxyType, p.JS
+	case ujeebu.ScrapeParams:
+		target, proxyType, js = p.URL, p.ProxyType, p.JS
+	case ujeebu.CardParams:
+		target, proxyType, js = p.URL, p.ProxyType, p.JS
+	default:
+		return attrs
+	}
+	if u, err := url.Parse(target); err == nil && u.Host != "" {
+		attrs = append(attrs, TargetHostKey.String(u.Hostname()))
+	}
+	if proxyType != "" {
+		attrs = append(attrs, ProxyTypeKey.String(proxyType))
+	}
+	return append(attrs, JSKey.Bool(js))
+}
+
+// retries returns the number of attempts after the first one
+func retries(meta ujeebu.ResponseMeta) int {
+	if meta.Attempts > 1 {
+		return meta.Attempts - 1
+	}
+	return 0
+}
+
+// errorType classifies an error: the APIError code, or its status code when there is none
+func errorType(err error) string {
+	var apiErr *ujeebu.APIError
+	var validationErr *ujeebu.ValidationError
+	var networkErr *ujeebu.NetworkError
+	switch {
+	case errors.As(err, &apiErr):
+		if apiErr.ErrorCode != nil {
+			return fmt.Sprint(apiErr.ErrorCode)
+		}
+		return strconv.Itoa(apiErr.StatusCode)
+	case errors.As(err, &validationErr):
+		return "validation"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "deadline_exceeded"
+	case errors.As(err, &networkErr):
+		return "network"
+	default:
+		return "_OTHER"
+	}
+}
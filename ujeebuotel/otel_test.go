@@ -0,0 +1,142 @@
+package ujeebuotel
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ujeebu/ujeebu-go"
+	"github.com/ujeebu/ujeebu-go/ujeebutest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setupInstrumentedClient(t *testing.T) (*ujeebutest.Server, *ujeebu.Client, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	server := ujeebutest.NewServer(t)
+	client := server.Client(
+		ujeebu.WithRetryPolicy(&ujeebu.BackoffPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}),
+		ClientOption(
+			WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+			WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		),
+	)
+	return server, client, spans, reader
+}
+
+func attrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Metrics {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	m := make(map[string]metricdata.Metrics)
+	for _, sm := range rm.ScopeMetrics {
+		assert.Equal(t, ScopeName, sm.Scope.Name)
+		for _, metric := range sm.Metrics {
+			m[metric.Name] = metric
+		}
+	}
+	return m
+}
+
+func TestMiddleware_Span(t *testing.T) {
+	server, client, spans, _ := setupInstrumentedClient(t)
+	server.Fail("card", ujeebutest.Fault{StatusCode: http.StatusServiceUnavailable, Times: 1})
+
+	_, _, err := client.Card(ujeebu.CardParams{URL: "https://www.example.com/page", JS: true, ProxyType: "residential"})
+	require.NoError(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+	span := ended[0]
+	assert.Equal(t, "ujeebu.card", span.Name())
+	a := attrs(span)
+	assert.Equal(t, "card", a[EndpointKey].AsString())
+	assert.Equal(t, "www.example.com", a[TargetHostKey].AsString())
+	assert.Equal(t, "residential", a[ProxyTypeKey].AsString())
+	assert.True(t, a[JSKey].AsBool())
+	assert.Equal(t, int64(http.StatusOK), a[StatusCodeKey].AsInt64())
+	assert.Equal(t, int64(ujeebutest.DefaultCredits["card"]), a[CreditsKey].AsInt64())
+	assert.Equal(t, int64(1), a[RetriesKey].AsInt64())
+	assert.Contains(t, a, LatencyKey)
+	assert.Equal(t, codes.Unset, span.Status().Code)
+}
+
+func TestMiddleware_Error(t *testing.T) {
+	server, client, spans, reader := setupInstrumentedClient(t)
+	server.Fail("extract", ujeebutest.Fault{StatusCode: http.StatusNotFound, ErrorCode: "NOT_FOUND", Message: "not found", Times: 1})
+	server.Fail("extract", ujeebutest.Fault{StatusCode: http.StatusBadRequest, Times: 1})
+
+	_, _, err := client.Extract(ujeebu.ExtractParams{URL: "https://example.com"})
+	require.Error(t, err)
+	_, _, err = client.Extract(ujeebu.ExtractParams{URL: "https://example.com"})
+	require.Error(t, err)
+	_, _, err = client.Extract(ujeebu.ExtractParams{})
+	require.Error(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 3)
+	assert.Equal(t, codes.Error, ended[0].Status().Code)
+	assert.Equal(t, "NOT_FOUND", attrs(ended[0])[ErrorTypeKey].AsString())
+	assert.Len(t, ended[0].Events(), 1)
+
+	errorsByType := make(map[string]int64)
+	sum := collect(t, reader)[ErrorsMetric].Data.(metricdata.Sum[int64])
+	for _, dp := range sum.DataPoints {
+		v, _ := dp.Attributes.Value(ErrorTypeKey)
+		errorsByType[v.AsString()] += dp.Value
+	}
+	assert.Equal(t, map[string]int64{"NOT_FOUND": 1, "400": 1, "validation": 1}, errorsByType)
+}
+
+func TestMiddleware_Metrics(t *testing.T) {
+	_, client, _, reader := setupInstrumentedClient(t)
+
+	for range 2 {
+		_, _, err := client.Extract(ujeebu.ExtractParams{URL: "https://example.com"})
+		require.NoError(t, err)
+	}
+	_, err := client.Account()
+	require.NoError(t, err)
+
+	metrics := collect(t, reader)
+
+	var requests int64
+	for _, dp := range metrics[RequestsMetric].Data.(metricdata.Sum[int64]).DataPoints {
+		requests += dp.Value
+	}
+	assert.Equal(t, int64(3), requests)
+
+	credits := metrics[CreditsMetric].Data.(metricdata.Sum[int64]).DataPoints
+	require.Len(t, credits, 1)
+	assert.Equal(t, int64(2*ujeebutest.DefaultCredits["extract"]), credits[0].Value)
+
+	var observed uint64
+	for _, dp := range metrics[DurationMetric].Data.(metricdata.Histogram[float64]).DataPoints {
+		observed += dp.Count
+	}
+	assert.Equal(t, uint64(3), observed)
+	assert.NotContains(t, metrics, ErrorsMetric)
+}
+
+func TestErrorType(t *testing.T) {
+	assert.Equal(t, "429", errorType(&ujeebu.APIError{StatusCode: http.StatusTooManyRequests}))
+	assert.Equal(t, "42", errorType(&ujeebu.APIError{StatusCode: http.StatusBadRequest, ErrorCode: 42}))
+	assert.Equal(t, "network", errorType(&ujeebu.NetworkError{Err: assert.AnError}))
+	assert.Equal(t, "deadline_exceeded", errorType(&ujeebu.NetworkError{Err: context.DeadlineExceeded}))
+	assert.Equal(t, "_OTHER", errorType(assert.AnError))
+}
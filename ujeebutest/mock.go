@@ -0,0 +1,392 @@
+package ujeebutest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ujeebu/ujeebu-go"
+)
+
+// Call is a method call recorded by MockAPI
+type Call struct {
+	// Method is the name of the ujeebu.API method called
+	Method string
+	// Params are the ExtractParams, ScrapeParams, CardParams or SerpParams passed, nil for Account methods
+	Params any
+}
+
+// MockAPI is an in-memory ujeebu.API for unit tests. It is safe for concurrent use.
+//
+// Each endpoint is served by a function mirroring its ...WithMeta method; the other
+// methods of the endpoint are derived from it the same way *ujeebu.Client does, so
+// Scrape, Screenshot and PDF all go through ScrapeFunc and the Google searches decode
+// the JSON returned by SerpFunc. A nil function returns an empty successful response.
+type MockAPI struct {
+	ExtractFunc func(ctx context.Context, params ujeebu.ExtractParams) (*ujeebu.ExtractResponse, ujeebu.ResponseMeta, error)
+	ScrapeFunc  func(ctx context.Context, params ujeebu.ScrapeParams) (*ujeebu.RawScrapeResponse, ujeebu.ResponseMeta, error)
+	CardFunc    func(ctx context.Context, params ujeebu.CardParams) (*ujeebu.CardResponse, ujeebu.ResponseMeta, error)
+	SerpFunc    func(ctx context.Context, params ujeebu.SerpParams) ([]byte, ujeebu.ResponseMeta, error)
+	AccountFunc func(ctx context.Context) (*ujeebu.AccountResponse, ujeebu.ResponseMeta, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ ujeebu.API = (*MockAPI)(nil)
+
+// Calls returns the calls made so far, in order
+func (m *MockAPI) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsTo returns the calls made to method
+func (m *MockAPI) CallsTo(method string) []Call {
+	var calls []Call
+	for _, call := range m.Calls() {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// ResetCalls forgets the recorded calls
+func (m *MockAPI) ResetCalls() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+}
+
+func (m *MockAPI) record(method string, params any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Method: method, Params: params})
+}
+
+// endpointMeta sets the endpoint of meta if the mock function left it empty
+func endpointMeta(meta ujeebu.ResponseMeta, endpoint string) ujeebu.ResponseMeta {
+	if meta.Endpoint == "" {
+		meta.Endpoint = endpoint
+	}
+	return meta
+}
+
+func (m *MockAPI) extract(ctx context.Context, params ujeebu.ExtractParams) (*ujeebu.ExtractResponse, ujeebu.ResponseMeta, error) {
+	if m.ExtractFunc == nil {
+		return &ujeebu.ExtractResponse{Article: &ujeebu.Article{URL: params.URL}}, ujeebu.ResponseMeta{Endpoint: "extract"}, nil
+	}
+	res, meta, err := m.ExtractFunc(ctx, params)
+	meta = endpointMeta(meta, "extract")
+	if err == nil && (res == nil || res.Article == nil) {
+		return nil, meta, fmt.Errorf("extract API response is not a valid ExtractResponse")
+	}
+	return res, meta, err
+}
+
+// Extract implements ujeebu.Extractor
+func (m *MockAPI) Extract(params ujeebu.ExtractParams) (*ujeebu.Article, int, error) {
+	m.record("Extract", params)
+	return articleResult(m.extract(context.Background(), params))
+}
+
+// ExtractWithContext implements ujeebu.Extractor
+func (m *MockAPI) ExtractWithContext(ctx context.Context, params ujeebu.ExtractParams) (*ujeebu.Article, int, error) {
+	m.record("ExtractWithContext", params)
+	return articleResult(m.extract(ctx, params))
+}
+
+// ExtractWithMeta implements ujeebu.Extractor
+func (m *MockAPI) ExtractWithMeta(ctx context.Context, params ujeebu.ExtractParams) (*ujeebu.ExtractResponse, ujeebu.ResponseMeta, error) {
+	m.record("ExtractWithMeta", params)
+	return m.extract(ctx, params)
+}
+
+func articleResult(res *ujeebu.ExtractResponse, meta ujeebu.ResponseMeta, err error) (*ujeebu.Article, int, error) {
+	if err != nil {
+		return nil, 0, err
+	}
+	return res.Article, meta.Credits, nil
+}
+
+func (m *MockAPI) scrapeRaw(ctx context.Context, params ujeebu.ScrapeParams) (*ujeebu.RawScrapeResponse, ujeebu.ResponseMeta, error) {
+	if m.ScrapeFunc == nil {
+		return &ujeebu.RawScrapeResponse{
+			Body:       []byte(`{"success":true}`),
+			StatusCode: http.StatusOK,
+			Headers:    http.Header{"Content-Type": {"application/json"}},
+		}, ujeebu.ResponseMeta{Endpoint: "scrape"}, nil
+	}
+	res, meta, err := m.ScrapeFunc(ctx, params)
+	return res, endpointMeta(meta, "scrape"), err
+}
+
+// scrape decodes the JSON output of ScrapeFunc as *ujeebu.Client does
+func (m *MockAPI) scrape(ctx context.Context, params ujeebu.ScrapeParams) (*ujeebu.ScrapeResponse, ujeebu.ResponseMeta, error) {
+	params.JSONOutput = true
+	raw, meta, err := m.scrapeRaw(ctx, params)
+	if err != nil {
+		return nil, meta, err
+	}
+	var res ujeebu.ScrapeResponse
+	if err := json.Unmarshal(raw.Body, &res); err != nil {
+		return nil, meta, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+	res.StatusCode = raw.StatusCode
+	res.ResponseHeaders = raw.Headers
+	return &res, meta, nil
+}
+
+// Scrape implements ujeebu.Scraper
+func (m *MockAPI) Scrape(params ujeebu.ScrapeParams) (*ujeebu.ScrapeResponse, int, error) {
+	m.record("Scrape", params)
+	res, meta, err := m.scrape(context.Background(), params)
+	return res, meta.Credits, err
+}
+
+// ScrapeWithContext implements ujeebu.Scraper
+func (m *MockAPI) ScrapeWithContext(ctx context.Context, params ujeebu.ScrapeParams) (*ujeebu.RawScrapeResponse, int, error) {
+	m.record("ScrapeWithContext", params)
+	res, meta, err := m.scrapeRaw(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	return res, meta.Credits, nil
+}
+
+// ScrapeWithMeta implements ujeebu.Scraper
+func (m *MockAPI) ScrapeWithMeta(ctx context.Context, params ujeebu.ScrapeParams) (*ujeebu.ScrapeResponse, ujeebu.ResponseMeta, error) {
+	m.record("ScrapeWithMeta", params)
+	return m.scrape(ctx, params)
+}
+
+// ScrapeRawWithMeta implements ujeebu.Scraper
+func (m *MockAPI) ScrapeRawWithMeta(ctx context.Context, params ujeebu.ScrapeParams) (*ujeebu.RawScrapeResponse, ujeebu.ResponseMeta, error) {
+	m.record("ScrapeRawWithMeta", params)
+	return m.scrapeRaw(ctx, params)
+}
+
+// Screenshot implements ujeebu.Scraper
+func (m *MockAPI) Screenshot(params ujeebu.ScrapeParams, fullPage bool, selector string) (string, int, error) {
+	m.record("Screenshot", params)
+	params.ResponseType = "screenshot"
+	params.ScreenshotFullPage = fullPage
+	params.ScreenshotPartial = selector
+	res, meta, err := m.scrape(context.Background(), params)
+	if err != nil {
+		return "", meta.Credits, err
+	}
+	return res.Screenshot, meta.Credits, nil
+}
+
+// PDF implements ujeebu.Scraper
+func (m *MockAPI) PDF(params ujeebu.ScrapeParams) (string, int, error) {
+	m.record("PDF", params)
+	params.ResponseType = "pdf"
+	res, meta, err := m.scrape(context.Background(), params)
+	if err != nil {
+		return "", meta.Credits, err
+	}
+	return res.PDF, meta.Credits, nil
+}
+
+// HTML implements ujeebu.Scraper
+func (m *MockAPI) HTML(params ujeebu.ScrapeParams) (string, int, error) {
+	m.record("HTML", params)
+	params.ResponseType = "html"
+	res, meta, err := m.scrape(context.Background(), params)
+	if err != nil {
+		return "", meta.Credits, err
+	}
+	return res.HTML, meta.Credits, nil
+}
+
+// Raw implements ujeebu.Scraper
+func (m *MockAPI) Raw(params ujeebu.ScrapeParams) (string, int, error) {
+	m.record("Raw", params)
+	params.ResponseType = "html"
+	res, meta, err := m.scrape(context.Background(), params)
+	if err != nil {
+		return "", meta.Credits, err
+	}
+	return res.HTMLSource, meta.Credits, nil
+}
+
+func (m *MockAPI) card(ctx context.Context, params ujeebu.CardParams) (*ujeebu.CardResponse, ujeebu.ResponseMeta, error) {
+	if m.CardFunc == nil {
+		return &ujeebu.CardResponse{URL: params.URL}, ujeebu.ResponseMeta{Endpoint: "card"}, nil
+	}
+	res, meta, err := m.CardFunc(ctx, params)
+	return res, endpointMeta(meta, "card"), err
+}
+
+// Card implements ujeebu.Carder
+func (m *MockAPI) Card(params ujeebu.CardParams) (*ujeebu.CardResponse, int, error) {
+	m.record("Card", params)
+	return cardResult(m.card(context.Background(), params))
+}
+
+// CardWithContext implements ujeebu.Carder
+func (m *MockAPI) CardWithContext(ctx context.Context, params ujeebu.CardParams) (*ujeebu.CardResponse, int, error) {
+	m.record("CardWithContext", params)
+	return cardResult(m.card(ctx, params))
+}
+
+// CardWithMeta implements ujeebu.Carder
+func (m *MockAPI) CardWithMeta(ctx context.Context, params ujeebu.CardParams) (*ujeebu.CardResponse, ujeebu.ResponseMeta, error) {
+	m.record("CardWithMeta", params)
+	return m.card(ctx, params)
+}
+
+func cardResult(res *ujeebu.CardResponse, meta ujeebu.ResponseMeta, err error) (*ujeebu.CardResponse, int, error) {
+	if err != nil {
+		return nil, 0, err
+	}
+	return res, meta.Credits, nil
+}
+
+func (m *MockAPI) serp(ctx context.Context, params ujeebu.SerpParams) ([]byte, ujeebu.ResponseMeta, error) {
+	if m.SerpFunc == nil {
+		return []byte(`{}`), ujeebu.ResponseMeta{Endpoint: "serp"}, nil
+	}
+	res, meta, err := m.SerpFunc(ctx, params)
+	return res, endpointMeta(meta, "serp"), err
+}
+
+// Serp implements ujeebu.Searcher
+func (m *MockAPI) Serp(params ujeebu.SerpParams) ([]byte, int, error) {
+	m.record("Serp", params)
+	return serpResult(m.serp(context.Background(), params))
+}
+
+// SerpWithContext implements ujeebu.Searcher
+func (m *MockAPI) SerpWithContext(ctx context.Context, params ujeebu.SerpParams) ([]byte, int, error) {
+	m.record("SerpWithContext", params)
+	return serpResult(m.serp(ctx, params))
+}
+
+// SerpWithMeta implements ujeebu.Searcher
+func (m *MockAPI) SerpWithMeta(ctx context.Context, params ujeebu.SerpParams) ([]byte, ujeebu.ResponseMeta, error) {
+	m.record("SerpWithMeta", params)
+	return m.serp(ctx, params)
+}
+
+func serpResult(res []byte, meta ujeebu.ResponseMeta, err error) ([]byte, int, error) {
+	if err != nil {
+		return nil, 0, err
+	}
+	return res, meta.Credits, nil
+}
+
+// search decodes the output of SerpFunc into T
+func search[T any](ctx context.Context, m *MockAPI, params ujeebu.SerpParams, label string) (T, int, error) {
+	var results T
+	body, meta, err := m.serp(ctx, params)
+	if err != nil {
+		return results, 0, err
+	}
+	if err := json.Unmarshal(body, &results); err != nil {
+		return results, 0, fmt.Errorf("failed to parse %s results: %w", label, err)
+	}
+	return results, meta.Credits, nil
+}
+
+// GoogleSearch implements ujeebu.Searcher
+func (m *MockAPI) GoogleSearch(params ujeebu.SerpParams) (ujeebu.GoogleSearchResult, int, error) {
+	m.record("GoogleSearch", params)
+	return search[ujeebu.GoogleSearchResult](context.Background(), m, params, "Google Search")
+}
+
+// GoogleSearchWithContext implements ujeebu.Searcher
+func (m *MockAPI) GoogleSearchWithContext(ctx context.Context, params ujeebu.SerpParams) (ujeebu.GoogleSearchResult, int, error) {
+	m.record("GoogleSearchWithContext", params)
+	return search[ujeebu.GoogleSearchResult](ctx, m, params, "Google Search")
+}
+
+// GoogleImageSearch implements ujeebu.Searcher
+func (m *MockAPI) GoogleImageSearch(params ujeebu.SerpParams) (ujeebu.GoogleImagesResult, int, error) {
+	m.record("GoogleImageSearch", params)
+	params.SearchType = "images"
+	return search[ujeebu.GoogleImagesResult](context.Background(), m, params, "Google Image")
+}
+
+// GoogleImageSearchWithContext implements ujeebu.Searcher
+func (m *MockAPI) GoogleImageSearchWithContext(ctx context.Context, params ujeebu.SerpParams) (ujeebu.GoogleImagesResult, int, error) {
+	m.record("GoogleImageSearchWithContext", params)
+	params.SearchType = "images"
+	return search[ujeebu.GoogleImagesResult](ctx, m, params, "Google Image")
+}
+
+// GoogleNewsSearch implements ujeebu.Searcher
+func (m *MockAPI) GoogleNewsSearch(params ujeebu.SerpParams) (ujeebu.GoogleNewsResult, int, error) {
+	m.record("GoogleNewsSearch", params)
+	params.SearchType = "news"
+	return search[ujeebu.GoogleNewsResult](context.Background(), m, params, "Google News")
+}
+
+// GoogleNewsSearchWithContext implements ujeebu.Searcher
+func (m *MockAPI) GoogleNewsSearchWithContext(ctx context.Context, params ujeebu.SerpParams) (ujeebu.GoogleNewsResult, int, error) {
+	m.record("GoogleNewsSearchWithContext", params)
+	params.SearchType = "news"
+	return search[ujeebu.GoogleNewsResult](ctx, m, params, "Google News")
+}
+
+// GoogleVideoSearch implements ujeebu.Searcher
+func (m *MockAPI) GoogleVideoSearch(params ujeebu.SerpParams) (ujeebu.GoogleVideosResult, int, error) {
+	m.record("GoogleVideoSearch", params)
+	params.SearchType = "videos"
+	return search[ujeebu.GoogleVideosResult](context.Background(), m, params, "Google Video")
+}
+
+// GoogleVideoSearchWithContext implements ujeebu.Searcher
+func (m *MockAPI) GoogleVideoSearchWithContext(ctx context.Context, params ujeebu.SerpParams) (ujeebu.GoogleVideosResult, int, error) {
+	m.record("GoogleVideoSearchWithContext", params)
+	params.SearchType = "videos"
+	return search[ujeebu.GoogleVideosResult](ctx, m, params, "Google Video")
+}
+
+// GoogleMapSearch implements ujeebu.Searcher
+func (m *MockAPI) GoogleMapSearch(params ujeebu.SerpParams) (ujeebu.GoogleMapsResult, int, error) {
+	m.record("GoogleMapSearch", params)
+	params.SearchType = "maps"
+	return search[ujeebu.GoogleMapsResult](context.Background(), m, params, "Google Map")
+}
+
+// GoogleMapSearchWithContext implements ujeebu.Searcher
+func (m *MockAPI) GoogleMapSearchWithContext(ctx context.Context, params ujeebu.SerpParams) (ujeebu.GoogleMapsResult, int, error) {
+	m.record("GoogleMapSearchWithContext", params)
+	params.SearchType = "maps"
+	return search[ujeebu.GoogleMapsResult](ctx, m, params, "Google Map")
+}
+
+func (m *MockAPI) account(ctx context.Context) (*ujeebu.AccountResponse, ujeebu.ResponseMeta, error) {
+	if m.AccountFunc == nil {
+		return &ujeebu.AccountResponse{}, ujeebu.ResponseMeta{Endpoint: "account"}, nil
+	}
+	res, meta, err := m.AccountFunc(ctx)
+	return res, endpointMeta(meta, "account"), err
+}
+
+// Account implements ujeebu.AccountReader
+func (m *MockAPI) Account() (*ujeebu.AccountResponse, error) {
+	m.record("Account", nil)
+	res, _, err := m.account(context.Background())
+	return res, err
+}
+
+// AccountWithContext implements ujeebu.AccountReader
+func (m *MockAPI) AccountWithContext(ctx context.Context) (*ujeebu.AccountResponse, error) {
+	m.record("AccountWithContext", nil)
+	res, _, err := m.account(ctx)
+	return res, err
+}
+
+// AccountWithMeta implements ujeebu.AccountReader
+func (m *MockAPI) AccountWithMeta(ctx context.Context) (*ujeebu.AccountResponse, ujeebu.ResponseMeta, error) {
+	m.record("AccountWithMeta", nil)
+	return m.account(ctx)
+}
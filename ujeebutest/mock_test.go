@@ -0,0 +1,154 @@
+package ujeebutest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ujeebu/ujeebu-go"
+)
+
+// titleOf depends on the narrow interface, as an application would
+func titleOf(e ujeebu.Extractor, url string) (string, error) {
+	article, _, err := e.Extract(ujeebu.ExtractParams{URL: url})
+	if err != nil {
+		return "", err
+	}
+	return article.Title, nil
+}
+
+func TestMockAPI_Extract(t *testing.T) {
+	mock := &MockAPI{
+		ExtractFunc: func(ctx context.Context, params ujeebu.ExtractParams) (*ujeebu.ExtractResponse, ujeebu.ResponseMeta, error) {
+			if params.URL == "https://broken.com" {
+				return nil, ujeebu.ResponseMeta{}, &ujeebu.APIError{StatusCode: http.StatusNotFound, Message: "not found"}
+			}
+			if params.URL == "https://empty.com" {
+				return nil, ujeebu.ResponseMeta{Credits: 5}, nil
+			}
+			return &ujeebu.ExtractResponse{Article: &ujeebu.Article{Title: "Hello"}}, ujeebu.ResponseMeta{Credits: 5}, nil
+		},
+	}
+
+	title, err := titleOf(mock, "https://example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", title)
+
+	_, err = titleOf(mock, "https://broken.com")
+	var apiErr *ujeebu.APIError
+	require.ErrorAs(t, err, &apiErr)
+
+	_, meta, err := mock.ExtractWithMeta(context.Background(), ujeebu.ExtractParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "extract", meta.Endpoint)
+	assert.Equal(t, 5, meta.Credits)
+
+	// A response without an article is an error, as with *ujeebu.Client
+	_, err = titleOf(mock, "https://empty.com")
+	assert.ErrorContains(t, err, "not a valid ExtractResponse")
+
+	calls := mock.CallsTo("Extract")
+	require.Len(t, calls, 3)
+	assert.Equal(t, "https://broken.com", calls[1].Params.(ujeebu.ExtractParams).URL)
+	assert.Len(t, mock.Calls(), 4)
+
+	mock.ResetCalls()
+	assert.Empty(t, mock.Calls())
+}
+
+func TestMockAPI_ScrapeHelpers(t *testing.T) {
+	var seen []ujeebu.ScrapeParams
+	mock := &MockAPI{
+		ScrapeFunc: func(ctx context.Context, params ujeebu.ScrapeParams) (*ujeebu.RawScrapeResponse, ujeebu.ResponseMeta, error) {
+			seen = append(seen, params)
+			body, _ := json.Marshal(ujeebu.ScrapeResponse{Success: true, Screenshot: "c2hvdA==", HTML: "<p>hi</p>"})
+			return &ujeebu.RawScrapeResponse{Body: body, StatusCode: http.StatusOK}, ujeebu.ResponseMeta{Credits: 2}, nil
+		},
+	}
+
+	shot, credits, err := mock.Screenshot(ujeebu.ScrapeParams{URL: "https://example.com"}, true, "")
+	require.NoError(t, err)
+	assert.Equal(t, "c2hvdA==", shot)
+	assert.Equal(t, 2, credits)
+
+	html, _, err := mock.HTML(ujeebu.ScrapeParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "<p>hi</p>", html)
+
+	require.Len(t, seen, 2)
+	assert.Equal(t, "screenshot", seen[0].ResponseType)
+	assert.True(t, seen[0].ScreenshotFullPage)
+	assert.True(t, seen[0].JSONOutput)
+	assert.Equal(t, []string{"Screenshot", "HTML"}, methods(mock.Calls()))
+}
+
+func TestMockAPI_Search(t *testing.T) {
+	mock := &MockAPI{
+		SerpFunc: func(ctx context.Context, params ujeebu.SerpParams) ([]byte, ujeebu.ResponseMeta, error) {
+			var body []byte
+			var err error
+			if params.SearchType == "news" {
+				body, err = json.Marshal(ujeebu.GoogleNewsResult{News: []ujeebu.News{{Title: "Go news"}}})
+			} else {
+				body, err = json.Marshal(ujeebu.GoogleSearchResult{OrganicResults: []ujeebu.OrganicResult{{Link: "https://go.dev"}}})
+			}
+			return body, ujeebu.ResponseMeta{Credits: 25}, err
+		},
+	}
+
+	var searcher ujeebu.Searcher = mock
+	results, _, err := searcher.GoogleSearch(ujeebu.SerpParams{Search: "golang"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://go.dev", results.OrganicResults[0].Link)
+
+	news, _, err := searcher.GoogleNewsSearchWithContext(context.Background(), ujeebu.SerpParams{Search: "golang"})
+	require.NoError(t, err)
+	assert.Equal(t, "Go news", news.News[0].Title)
+}
+
+func TestMockAPI_Defaults(t *testing.T) {
+	var api ujeebu.API = &MockAPI{}
+
+	article, _, err := api.Extract(ujeebu.ExtractParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", article.URL)
+
+	card, _, err := api.Card(ujeebu.CardParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", card.URL)
+
+	res, _, err := api.Scrape(ujeebu.ScrapeParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.True(t, res.Success)
+
+	_, _, err = api.GoogleMapSearch(ujeebu.SerpParams{Search: "cafe"})
+	require.NoError(t, err)
+
+	account, err := api.Account()
+	require.NoError(t, err)
+	assert.NotNil(t, account)
+}
+
+func TestMockAPI_AccountError(t *testing.T) {
+	boom := errors.New("boom")
+	mock := &MockAPI{
+		AccountFunc: func(ctx context.Context) (*ujeebu.AccountResponse, ujeebu.ResponseMeta, error) {
+			return nil, ujeebu.ResponseMeta{}, boom
+		},
+	}
+	_, err := mock.AccountWithContext(context.Background())
+	assert.ErrorIs(t, err, boom)
+	assert.Nil(t, mock.CallsTo("AccountWithContext")[0].Params)
+}
+
+func methods(calls []Call) []string {
+	names := make([]string, len(calls))
+	for i, call := range calls {
+		names[i] = call.Method
+	}
+	return names
+}
@@ -0,0 +1,310 @@
+// Package recorder records Ujeebu API calls into cassette files and replays them,
+// so tests can exercise real payloads without network access or an API key.
+//
+//	rec, err := recorder.New("testdata/extract.json", recorder.Options{Mode: recorder.ModeAuto, Strict: true})
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer rec.Stop()
+//
+//	client, err := ujeebu.NewClient("test-key", rec.ClientOption())
+package recorder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/ujeebu/ujeebu-go"
+)
+
+// ErrNoMatch is returned in strict mode when a request matches no recorded interaction
+var ErrNoMatch = errors.New("recorder: no recorded interaction matches the request")
+
+// Mode selects whether a Recorder sends requests or replays a cassette
+type Mode int
+
+const (
+	// ModeReplay serves requests from the cassette, which must exist
+	ModeReplay Mode = iota
+	// ModeRecord sends every request and records it, replacing the cassette on Stop
+	ModeRecord
+	// ModeAuto replays the cassette if it exists and records a new one otherwise
+	ModeAuto
+)
+
+// DefaultScrubbedHeaders are the request headers never written to cassettes
+var DefaultScrubbedHeaders = []string{"ApiKey"}
+
+// Options configures a Recorder
+type Options struct {
+	// Mode selects recording or replaying (ModeReplay by default)
+	Mode Mode
+	// Strict makes unmatched requests fail with ErrNoMatch while replaying.
+	// Otherwise they are sent with Transport.
+	Strict bool
+	// Transport sends the requests that are not replayed (http.DefaultTransport if nil)
+	Transport http.RoundTripper
+	// ScrubHeaders are removed from recorded requests in addition to DefaultScrubbedHeaders
+	ScrubHeaders []string
+}
+
+// Cassette is the content of a cassette file
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request/response pair
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded HTTP request
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// Response is a recorded HTTP response.
+// Bodies that are not valid UTF-8 are stored base64-encoded with BodyEncoding set to "base64".
+type Response struct {
+	StatusCode   int         `json:"status_code"`
+	Headers      http.Header `json:"headers,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"`
+}
+
+// Recorder is an http.RoundTripper recording or replaying API calls. It is safe for concurrent use.
+type Recorder struct {
+	path      string
+	opts      Options
+	recording bool
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New returns a Recorder for the cassette file at path
+func New(path string, opts Options) (*Recorder, error) {
+	if opts.Transport == nil {
+		opts.Transport = http.DefaultTransport
+	}
+	r := &Recorder{path: path, opts: opts}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case opts.Mode == ModeRecord:
+		r.recording = true
+	case errors.Is(err, fs.ErrNotExist) && opts.Mode == ModeAuto:
+		r.recording = true
+	case err != nil:
+		return nil, fmt.Errorf("recorder: reading cassette: %w", err)
+	default:
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("recorder: decoding cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// Recording reports whether the Recorder sends and records requests
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+// ClientOption returns the option making a ujeebu.Client send its requests through the Recorder
+func (r *Recorder) ClientOption() ujeebu.ClientOption {
+	return ujeebu.WithTransport(r)
+}
+
+// Cassette returns a copy of the interactions recorded or loaded so far
+func (r *Recorder) Cassette() Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+// Stop writes the cassette file when recording
+func (r *Recorder) Stop() error {
+	if !r.recording {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.recording {
+		if recorded, ok := r.match(req, body); ok {
+			return recorded.toHTTP(req), nil
+		}
+		if r.opts.Strict {
+			return nil, fmt.Errorf("%w: %s %s", ErrNoMatch, req.Method, req.URL.RequestURI())
+		}
+		return r.opts.Transport.RoundTrip(req)
+	}
+
+	resp, err := r.opts.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request:  r.recordRequest(req, body),
+		Response: recordResponse(resp, respBody),
+	})
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// match returns the first unused interaction matching the request.
+// Once all matching interactions were used, the last one is replayed again.
+func (r *Recorder) match(req *http.Request, body []byte) (Response, bool) {
+	key := matchKey(req.Method, req.URL.Path, req.URL.Query().Encode(), body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	last := -1
+	for i, interaction := range r.cassette.Interactions {
+		if interaction.Request.key() != key {
+			continue
+		}
+		if !r.used[i] {
+			r.used[i] = true
+			return interaction.Response, true
+		}
+		last = i
+	}
+	if last >= 0 {
+		return r.cassette.Interactions[last].Response, true
+	}
+	return Response{}, false
+}
+
+// recordRequest returns the request to record, without the scrubbed headers
+func (r *Recorder) recordRequest(req *http.Request, body []byte) Request {
+	headers := req.Header.Clone()
+	for _, name := range append(append([]string(nil), DefaultScrubbedHeaders...), r.opts.ScrubHeaders...) {
+		headers.Del(name)
+	}
+	return Request{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Headers: headers,
+		Body:    string(body),
+	}
+}
+
+// key returns the matching key of a recorded request
+func (rr Request) key() string {
+	path, query := rr.URL, ""
+	if u, err := url.Parse(rr.URL); err == nil {
+		path, query = u.Path, u.Query().Encode()
+	}
+	return matchKey(rr.Method, path, query, []byte(rr.Body))
+}
+
+// matchKey identifies a request by method, path, sorted query and normalized body
+func matchKey(method, path, query string, body []byte) string {
+	return strings.ToUpper(method) + " " + path + "?" + query + "\n" + string(normalizeBody(body))
+}
+
+// normalizeBody re-encodes JSON bodies so key order and whitespace do not matter
+func normalizeBody(body []byte) []byte {
+	var v any
+	if len(body) == 0 || json.Unmarshal(body, &v) != nil {
+		return body
+	}
+	normalized, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return normalized
+}
+
+func recordResponse(resp *http.Response, body []byte) Response {
+	recorded := Response{StatusCode: resp.StatusCode, Headers: resp.Header.Clone()}
+	if utf8.Valid(body) {
+		recorded.Body = string(body)
+	} else {
+		recorded.Body = base64.StdEncoding.EncodeToString(body)
+		recorded.BodyEncoding = "base64"
+	}
+	return recorded
+}
+
+// toHTTP builds the replayed response of req
+func (rr Response) toHTTP(req *http.Request) *http.Response {
+	body := []byte(rr.Body)
+	if rr.BodyEncoding == "base64" {
+		if decoded, err := base64.StdEncoding.DecodeString(rr.Body); err == nil {
+			body = decoded
+		}
+	}
+	headers := rr.Headers.Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rr.StatusCode, http.StatusText(rr.StatusCode)),
+		StatusCode:    rr.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        headers,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// readBody reads the request body and restores it for the transport
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
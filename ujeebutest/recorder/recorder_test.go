@@ -0,0 +1,133 @@
+package recorder
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ujeebu/ujeebu-go"
+)
+
+func newUpstream(t *testing.T) (*httptest.Server, *int) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(ujeebu.CreditsHeader, "5")
+		switch r.URL.Path {
+		case "/card":
+			_, _ = w.Write([]byte(`{"url": "` + r.URL.Query().Get("url") + `", "title": "Recorded"}`))
+		case "/scrape":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte{0x89, 'P', 'N', 'G', 0xff, 0x00})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "not found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	upstream, calls := newUpstream(t)
+	path := filepath.Join(t.TempDir(), "cassettes", "card.json")
+
+	rec, err := New(path, Options{Mode: ModeAuto})
+	require.NoError(t, err)
+	assert.True(t, rec.Recording())
+
+	client, err := ujeebu.NewClient("secret-key", ujeebu.WithBaseURL(upstream.URL), rec.ClientOption())
+	require.NoError(t, err)
+	card, credits, err := client.Card(ujeebu.CardParams{URL: "https://example.com", JS: true})
+	require.NoError(t, err)
+	assert.Equal(t, "Recorded", card.Title)
+	assert.Equal(t, 5, credits)
+	shot, _, err := client.ScrapeWithContext(context.Background(), ujeebu.ScrapeParams{URL: "https://example.com", ResponseType: "screenshot"})
+	require.NoError(t, err)
+	require.NoError(t, rec.Stop())
+	assert.Equal(t, 2, *calls)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret-key")
+	assert.Contains(t, string(data), `"body_encoding": "base64"`)
+
+	// Replay without the upstream server, from a different base URL and parameter order
+	rec, err = New(path, Options{Mode: ModeAuto, Strict: true})
+	require.NoError(t, err)
+	assert.False(t, rec.Recording())
+
+	client, err = ujeebu.NewClient("other-key", ujeebu.WithBaseURL("http://replay.invalid"), rec.ClientOption())
+	require.NoError(t, err)
+	card, credits, err = client.Card(ujeebu.CardParams{JS: true, URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "Recorded", card.Title)
+	assert.Equal(t, 5, credits)
+	replayed, _, err := client.ScrapeWithContext(context.Background(), ujeebu.ScrapeParams{URL: "https://example.com", ResponseType: "screenshot"})
+	require.NoError(t, err)
+	assert.Equal(t, shot.Body, replayed.Body)
+	assert.Equal(t, 2, *calls)
+
+	_, _, err = client.Card(ujeebu.CardParams{URL: "https://other.com"})
+	require.ErrorIs(t, err, ErrNoMatch)
+}
+
+func TestRecorder_ReplayErrorsAndPassthrough(t *testing.T) {
+	upstream, calls := newUpstream(t)
+	path := filepath.Join(t.TempDir(), "errors.json")
+
+	_, err := New(path, Options{Mode: ModeReplay})
+	require.Error(t, err)
+
+	rec, err := New(path, Options{Mode: ModeRecord})
+	require.NoError(t, err)
+	client, err := ujeebu.NewClient("secret-key", ujeebu.WithBaseURL(upstream.URL), rec.ClientOption())
+	require.NoError(t, err)
+	_, _, err = client.Serp(ujeebu.SerpParams{Search: "golang"})
+	var apiErr *ujeebu.APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.NoError(t, rec.Stop())
+
+	// Recorded API errors replay as API errors; unmatched requests reach upstream when not strict
+	rec, err = New(path, Options{})
+	require.NoError(t, err)
+	client, err = ujeebu.NewClient("secret-key", ujeebu.WithBaseURL(upstream.URL), rec.ClientOption())
+	require.NoError(t, err)
+	_, _, err = client.Serp(ujeebu.SerpParams{Search: "golang"})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, 1, *calls)
+
+	_, _, err = client.Card(ujeebu.CardParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, *calls)
+}
+
+func TestRecorder_ScrubHeaders(t *testing.T) {
+	upstream, _ := newUpstream(t)
+	rec, err := New(filepath.Join(t.TempDir(), "scrub.json"), Options{Mode: ModeRecord, ScrubHeaders: []string{"UJB-Cookie"}})
+	require.NoError(t, err)
+
+	client, err := ujeebu.NewClient("secret-key", ujeebu.WithBaseURL(upstream.URL), rec.ClientOption())
+	require.NoError(t, err)
+	_, _, err = client.Card(ujeebu.CardParams{URL: "https://example.com", CustomHeaders: map[string]string{"Cookie": "session=1", "Accept-Language": "fr"}})
+	require.NoError(t, err)
+
+	recorded := rec.Cassette().Interactions[0].Request
+	assert.Empty(t, recorded.Headers.Get("ApiKey"))
+	assert.Empty(t, recorded.Headers.Get("UJB-Cookie"))
+	assert.Equal(t, "fr", recorded.Headers.Get("UJB-Accept-Language"))
+}
+
+func TestMatchKey_NormalizesJSONBody(t *testing.T) {
+	a := matchKey("post", "/extract", "", []byte(`{"url": "https://example.com", "js": true}`))
+	b := matchKey("POST", "/extract", "", []byte(`{"js":true,"url":"https://example.com"}`))
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, matchKey("POST", "/extract", "", []byte(`{"js":false,"url":"https://example.com"}`)))
+}
@@ -0,0 +1,323 @@
+package ujeebutest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ujeebu/ujeebu-go"
+)
+
+// Minimal but valid file signatures returned for screenshots and PDFs
+var (
+	// PNG is the screenshot returned by the fake scrape endpoint
+	PNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00\x1f\x15\xc4\x89")
+	// PDF is the document returned by the fake scrape endpoint
+	PDF = []byte("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\ntrailer << /Root 1 0 R >>\n%%EOF\n")
+)
+
+// defaultHandler returns the built-in response of endpoint
+func (s *Server) defaultHandler(endpoint string) http.HandlerFunc {
+	switch endpoint {
+	case "extract":
+		return handleExtract
+	case "scrape":
+		return handleScrape
+	case "card":
+		return handleCard
+	case "serp":
+		return handleSerp
+	case "account":
+		return func(w http.ResponseWriter, r *http.Request) {
+			s.mu.Lock()
+			account := s.account()
+			s.mu.Unlock()
+			writeJSON(w, http.StatusOK, account)
+		}
+	}
+	return nil
+}
+
+// page describes the fake page behind a requested URL
+type page struct {
+	url   string
+	host  string
+	title string
+}
+
+// requestedPage returns the page of the url parameter, writing a 400 error if it is missing
+func requestedPage(w http.ResponseWriter, r *http.Request) (page, bool) {
+	raw := requestParam(r, "url")
+	u, err := url.Parse(raw)
+	if raw == "" || err != nil || u.Host == "" {
+		writeError(w, r, Fault{StatusCode: http.StatusBadRequest, Message: "URL is required and must be absolute", ErrorCode: "INVALID_URL"})
+		return page{}, false
+	}
+	name := strings.Trim(u.Path, "/")
+	if name == "" {
+		name = "home"
+	}
+	return page{url: raw, host: u.Host, title: fmt.Sprintf("%s | %s", strings.ReplaceAll(name, "-", " "), u.Host)}, true
+}
+
+func (p page) html() string {
+	return fmt.Sprintf(`<!DOCTYPE html><html lang="en"><head><title>%s</title></head>`+
+		`<body><h1>%s</h1><p>First paragraph of %s.</p><p>Second paragraph.</p>`+
+		`<a href="https://%s/about">About</a></body></html>`, p.title, p.title, p.url, p.host)
+}
+
+func handleExtract(w http.ResponseWriter, r *http.Request) {
+	p, ok := requestedPage(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, ujeebu.ExtractResponse{
+		Article: &ujeebu.Article{
+			URL:          p.url,
+			CanonicalURL: p.url,
+			Title:        p.title,
+			Text:         fmt.Sprintf("First paragraph of %s.\nSecond paragraph.", p.url),
+			HTML:         fmt.Sprintf("<p>First paragraph of %s.</p><p>Second paragraph.</p>", p.url),
+			Summary:      "First paragraph of " + p.url + ".",
+			Image:        "https://" + p.host + "/cover.jpg",
+			Images:       []string{"https://" + p.host + "/cover.jpg"},
+			Media:        []string{},
+			Language:     "en",
+			Author:       "Jane Doe",
+			PubDate:      "2024-01-15 09:30:00",
+			ModifiedDate: "2024-01-16 10:00:00",
+			SiteName:     p.host,
+			Favicon:      "https://" + p.host + "/favicon.ico",
+			Encoding:     "utf-8",
+			IsArticle:    1,
+			Pages:        []string{p.url},
+		},
+		Time: 0.42,
+		JS:   requestParam(r, "js") == "true",
+	})
+}
+
+func handleCard(w http.ResponseWriter, r *http.Request) {
+	p, ok := requestedPage(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, ujeebu.CardResponse{
+		URL:           p.url,
+		Lang:          "en",
+		Favicon:       "https://" + p.host + "/favicon.ico",
+		Title:         p.title,
+		Summary:       "First paragraph of " + p.url + ".",
+		Author:        "Jane Doe",
+		DatePublished: "2024-01-15 09:30:00",
+		DateModified:  "2024-01-16 10:00:00",
+		Image:         "https://" + p.host + "/cover.jpg",
+		SiteName:      p.host,
+		Charset:       "utf-8",
+		Keywords:      []string{"example", "test"},
+		Time:          0.12,
+	})
+}
+
+func handleScrape(w http.ResponseWriter, r *http.Request) {
+	p, ok := requestedPage(w, r)
+	if !ok {
+		return
+	}
+
+	if rules := requestParam(r, "extract_rules"); rules != "" {
+		var parsed map[string]any
+		if err := json.Unmarshal([]byte(rules), &parsed); err != nil {
+			writeError(w, r, Fault{StatusCode: http.StatusBadRequest, Message: "Invalid extract_rules", ErrorCode: "INVALID_RULES"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"success": true, "result": ruleResults(parsed, p)})
+		return
+	}
+
+	asJSON := requestParam(r, "json") == "true"
+	switch requestParam(r, "response_type") {
+	case "screenshot":
+		if asJSON {
+			writeJSON(w, http.StatusOK, ujeebu.ScrapeResponse{Success: true, Screenshot: base64.StdEncoding.EncodeToString(PNG)})
+			return
+		}
+		writeBody(w, "image/png", PNG)
+	case "pdf":
+		if asJSON {
+			writeJSON(w, http.StatusOK, ujeebu.ScrapeResponse{Success: true, PDF: base64.StdEncoding.EncodeToString(PDF)})
+			return
+		}
+		writeBody(w, "application/pdf", PDF)
+	default:
+		if asJSON {
+			writeJSON(w, http.StatusOK, ujeebu.ScrapeResponse{Success: true, HTML: p.html(), HTMLSource: p.html()})
+			return
+		}
+		writeBody(w, "text/html; charset=utf-8", []byte(p.html()))
+	}
+}
+
+// ruleResults returns a sample value for each extraction rule
+func ruleResults(rules map[string]any, p page) map[string]any {
+	results := map[string]any{}
+	for name, raw := range rules {
+		rule, _ := raw.(map[string]any)
+		var value any
+		switch rule["type"] {
+		case "link":
+			value = "https://" + p.host + "/" + name
+		case "image":
+			value = "https://" + p.host + "/" + name + ".jpg"
+		case "obj":
+			children, _ := rule["children"].(map[string]any)
+			value = ruleResults(children, p)
+		default:
+			value = name + " of " + p.title
+		}
+		if multiple, _ := rule["multiple"].(bool); multiple {
+			value = []any{value, value}
+		}
+		results[name] = value
+	}
+	return results
+}
+
+func handleSerp(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	search := q.Get("search")
+	if search == "" && q.Get("url") == "" {
+		writeError(w, r, Fault{StatusCode: http.StatusBadRequest, Message: "Either search or url is required", ErrorCode: "MISSING_PARAMS"})
+		return
+	}
+	if search == "" {
+		search = q.Get("url")
+	}
+
+	pageNum, _ := strconv.Atoi(q.Get("page"))
+	if pageNum < 1 {
+		pageNum = 1
+	}
+	count, _ := strconv.Atoi(q.Get("results_count"))
+	if count < 1 {
+		count = 10
+	}
+	first := (pageNum-1)*count + 1
+	base := ujeebu.BaseResponse{
+		Metadata: ujeebu.ResponseMetadata{
+			GoogleUrl:       "https://www.google.com/search?q=" + url.QueryEscape(search),
+			NumberOfResults: 1000,
+			QueryDisplayed:  search,
+			ResultsTime:     "0.31 seconds",
+		},
+		Pagination: ujeebu.ResponsePagination{
+			Google: ujeebu.PaginationLinks{Current: fmt.Sprintf("https://www.google.com/search?q=%s&start=%d", url.QueryEscape(search), first-1)},
+			Api: ujeebu.PaginationLinks{
+				Current: serpPageURL(q, pageNum),
+				Next:    serpPageURL(q, pageNum+1),
+				OtherPages: map[int]string{
+					pageNum + 1: serpPageURL(q, pageNum+1),
+					pageNum + 2: serpPageURL(q, pageNum+2),
+				},
+			},
+		},
+	}
+	slug := strings.ReplaceAll(strings.ToLower(search), " ", "-")
+
+	switch q.Get("search_type") {
+	case "images":
+		res := ujeebu.GoogleImagesResult{BaseResponse: base}
+		for i := 0; i < count; i++ {
+			res.Images = append(res.Images, ujeebu.GoogleImage{
+				GoogleThumbnail: fmt.Sprintf("https://encrypted-tbn0.gstatic.com/images?q=%s-%d", slug, first+i),
+				Height:          600,
+				Width:           800,
+				Image:           fmt.Sprintf("https://images.example.com/%s-%d.jpg", slug, first+i),
+				Link:            fmt.Sprintf("https://images.example.com/%s-%d", slug, first+i),
+				Position:        first + i,
+				Source:          "images.example.com",
+				Title:           fmt.Sprintf("%s image %d", search, first+i),
+			})
+		}
+		writeJSON(w, http.StatusOK, res)
+	case "news":
+		res := ujeebu.GoogleNewsResult{BaseResponse: base}
+		for i := 0; i < count; i++ {
+			res.News = append(res.News, ujeebu.News{
+				Date:        "2 hours ago",
+				Description: fmt.Sprintf("News about %s.", search),
+				Link:        fmt.Sprintf("https://news.example.com/%s-%d", slug, first+i),
+				Position:    first + i,
+				SiteName:    "news.example.com",
+				Title:       fmt.Sprintf("%s news %d", search, first+i),
+			})
+		}
+		writeJSON(w, http.StatusOK, res)
+	case "videos":
+		res := ujeebu.GoogleVideosResult{BaseResponse: base}
+		for i := 0; i < count; i++ {
+			res.Videos = append(res.Videos, ujeebu.GoogleVideo{
+				Author:   "Example Channel",
+				Date:     "Jan 15, 2024",
+				Position: first + i,
+				Provider: "YouTube",
+				Site:     "www.youtube.com",
+				Title:    fmt.Sprintf("%s video %d", search, first+i),
+				Url:      fmt.Sprintf("https://www.youtube.com/watch?v=%s-%d", slug, first+i),
+			})
+		}
+		writeJSON(w, http.StatusOK, res)
+	case "maps":
+		res := ujeebu.GoogleMapsResult{BaseResponse: base}
+		for i := 0; i < count; i++ {
+			res.Maps = append(res.Maps, ujeebu.GoogleMap{
+				Address:  fmt.Sprintf("%d Main St", first+i),
+				Category: "Business",
+				Cid:      strconv.Itoa(1000 + first + i),
+				Position: first + i,
+				Rating:   4.5,
+				Reviews:  100 + i,
+				Title:    fmt.Sprintf("%s place %d", search, first+i),
+			})
+		}
+		writeJSON(w, http.StatusOK, res)
+	default:
+		res := ujeebu.GoogleSearchResult{BaseResponse: base}
+		for i := 0; i < count; i++ {
+			res.OrganicResults = append(res.OrganicResults, ujeebu.OrganicResult{
+				Cite:        fmt.Sprintf("https://site%d.example.com", first+i),
+				Link:        fmt.Sprintf("https://site%d.example.com/%s", first+i, slug),
+				Position:    first + i,
+				SiteName:    fmt.Sprintf("site%d.example.com", first+i),
+				Title:       fmt.Sprintf("%s - result %d", search, first+i),
+				Description: fmt.Sprintf("Everything about %s.", search),
+			})
+		}
+		if pageNum == 1 {
+			res.KnowledgeGraph = ujeebu.KnowledgeGraph{Title: search, Type: "Topic"}
+			res.RelatedQuestions = []string{"What is " + search + "?"}
+			res.RelatedSearches = []ujeebu.RelatedSearch{{Query: search + " tutorial"}}
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// serpPageURL returns the API link to another page of the same search
+func serpPageURL(q url.Values, page int) string {
+	next := url.Values{}
+	for name, values := range q {
+		next[name] = values
+	}
+	next.Set("page", strconv.Itoa(page))
+	return "https://api.ujeebu.com/serp?" + next.Encode()
+}
+
+func writeBody(w http.ResponseWriter, contentType string, body []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,395 @@
+// Package ujeebutest provides a fake Ujeebu API server for testing code that uses the SDK.
+//
+//	srv := ujeebutest.NewServer(t)
+//	srv.Fail("extract", ujeebutest.Fault{StatusCode: http.StatusTooManyRequests, Times: 1})
+//	client := srv.Client(ujeebu.WithRetry(2, time.Millisecond, time.Millisecond))
+//
+//	article, credits, err := client.Extract(ujeebu.ExtractParams{URL: "https://example.com/post"})
+//	srv.AssertCalls(t, "extract", 2)
+package ujeebutest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ujeebu/ujeebu-go"
+)
+
+// TestAPIKey is the API key used by clients returned by Server.Client
+const TestAPIKey = "ujeebutest-api-key"
+
+// DefaultBalance is the account balance a Server starts with
+const DefaultBalance = 5000
+
+// DefaultCredits are the credits charged by a Server for each endpoint
+var DefaultCredits = map[string]int{
+	"extract": 5,
+	"scrape":  1,
+	"card":    1,
+	"serp":    25,
+	"account": 0,
+}
+
+// Fault is an error returned by a Server instead of the normal response
+type Fault struct {
+	// StatusCode is the HTTP status of the error response
+	StatusCode int
+	// Message is the APIError message (the status text if empty)
+	Message string
+	// ErrorCode is the APIError error code (the status code if nil)
+	ErrorCode any
+	// RetryAfter sets the Retry-After header when positive
+	RetryAfter time.Duration
+	// Times is the number of requests that fail, 0 for all of them
+	Times int
+}
+
+// Request is a request received by a Server
+type Request struct {
+	// Endpoint is the endpoint name (extract, scrape, card, serp, account)
+	Endpoint string
+	Method   string
+	Query    url.Values
+	Header   http.Header
+	Body     []byte
+}
+
+// Param returns the value of a parameter sent in the query string or the JSON body
+func (r Request) Param(name string) string {
+	if value := r.Query.Get(name); value != "" {
+		return value
+	}
+	if len(r.Body) == 0 {
+		return ""
+	}
+	var body map[string]any
+	if err := json.Unmarshal(r.Body, &body); err != nil {
+		return ""
+	}
+	switch v := body[name].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool, float64:
+		return fmt.Sprint(v)
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// Server is a fake Ujeebu API. It is safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the server
+	URL string
+
+	server *httptest.Server
+
+	mu       sync.Mutex
+	apiKey   string
+	latency  map[string]time.Duration
+	credits  map[string]int
+	faults   map[string][]*Fault
+	handlers map[string]http.HandlerFunc
+	balance  int
+	used     int
+	requests []Request
+}
+
+// NewServer starts a Server that is closed when the test ends
+func NewServer(tb testing.TB) *Server {
+	s := &Server{
+		latency:  map[string]time.Duration{},
+		credits:  map[string]int{},
+		faults:   map[string][]*Fault{},
+		handlers: map[string]http.HandlerFunc{},
+		balance:  DefaultBalance,
+	}
+	for endpoint, credits := range DefaultCredits {
+		s.credits[endpoint] = credits
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+	if tb != nil {
+		tb.Cleanup(s.Close)
+	}
+	return s
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client returns a client for the server, using TestAPIKey
+func (s *Server) Client(opts ...ujeebu.ClientOption) *ujeebu.Client {
+	client, err := ujeebu.NewClient(TestAPIKey, append([]ujeebu.ClientOption{ujeebu.WithBaseURL(s.URL)}, opts...)...)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+// SetAPIKey makes the server reject requests with another API key with a 401 error.
+// By default any non-empty key is accepted.
+func (s *Server) SetAPIKey(apiKey string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apiKey = apiKey
+}
+
+// SetLatency delays the responses of endpoint, or of all endpoints if endpoint is empty
+func (s *Server) SetLatency(endpoint string, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency[endpoint] = latency
+}
+
+// SetCredits sets the credits charged for endpoint
+func (s *Server) SetCredits(endpoint string, credits int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.credits[endpoint] = credits
+}
+
+// SetBalance sets the account balance. Calls costing more than the balance fail with a 402 error.
+func (s *Server) SetBalance(balance int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balance = balance
+}
+
+// Fail makes endpoint, or all endpoints if endpoint is empty, return fault.
+// Faults are applied in the order they were added.
+func (s *Server) Fail(endpoint string, fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults[endpoint] = append(s.faults[endpoint], &fault)
+}
+
+// Handle replaces the default response of endpoint.
+// Authentication, latency, faults and credits still apply.
+func (s *Server) Handle(endpoint string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[endpoint] = handler
+}
+
+// Requests returns the requests received for endpoint, or all requests if endpoint is empty
+func (s *Server) Requests(endpoint string) []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var requests []Request
+	for _, r := range s.requests {
+		if endpoint == "" || r.Endpoint == endpoint {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+// LastRequest returns the last request received for endpoint
+func (s *Server) LastRequest(endpoint string) (Request, bool) {
+	requests := s.Requests(endpoint)
+	if len(requests) == 0 {
+		return Request{}, false
+	}
+	return requests[len(requests)-1], true
+}
+
+// Reset forgets the received requests, faults, handlers and spent credits
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+	s.faults = map[string][]*Fault{}
+	s.handlers = map[string]http.HandlerFunc{}
+	s.balance += s.used
+	s.used = 0
+}
+
+// AssertCalls checks that endpoint received n requests
+func (s *Server) AssertCalls(tb testing.TB, endpoint string, n int) bool {
+	tb.Helper()
+	if got := len(s.Requests(endpoint)); got != n {
+		tb.Errorf("ujeebutest: expected %d %s requests, got %d", n, endpoint, got)
+		return false
+	}
+	return true
+}
+
+// AssertParam checks that the last request to endpoint had parameter name set to want
+func (s *Server) AssertParam(tb testing.TB, endpoint, name, want string) bool {
+	tb.Helper()
+	r, ok := s.LastRequest(endpoint)
+	if !ok {
+		tb.Errorf("ujeebutest: no %s request received", endpoint)
+		return false
+	}
+	if got := r.Param(name); got != want {
+		tb.Errorf("ujeebutest: expected %s parameter %q to be %q, got %q", endpoint, name, want, got)
+		return false
+	}
+	return true
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint := strings.Trim(r.URL.Path, "/")
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Endpoint: endpoint,
+		Method:   r.Method,
+		Query:    r.URL.Query(),
+		Header:   r.Header.Clone(),
+		Body:     body,
+	})
+	latency := s.latency[""] + s.latency[endpoint]
+	apiKey := s.apiKey
+	handler := s.handlers[endpoint]
+	credits := s.credits[endpoint]
+	s.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	key := r.Header.Get("ApiKey")
+	if key == "" || (apiKey != "" && key != apiKey) {
+		writeError(w, r, Fault{StatusCode: http.StatusUnauthorized, Message: "Invalid API key"})
+		return
+	}
+
+	s.mu.Lock()
+	fault := s.nextFault(endpoint)
+	s.mu.Unlock()
+	if fault != nil {
+		writeError(w, r, *fault)
+		return
+	}
+
+	if handler == nil {
+		handler = s.defaultHandler(endpoint)
+	}
+	if handler == nil {
+		writeError(w, r, Fault{StatusCode: http.StatusNotFound, Message: "Unknown endpoint " + r.URL.Path})
+		return
+	}
+
+	rec := httptest.NewRecorder()
+	handler(rec, r)
+	if rec.Code < 300 {
+		s.mu.Lock()
+		if credits > s.balance {
+			s.mu.Unlock()
+			writeError(w, r, Fault{StatusCode: http.StatusPaymentRequired, Message: "Insufficient credits"})
+			return
+		}
+		s.balance -= credits
+		s.used += credits
+		s.mu.Unlock()
+		if endpoint != "account" {
+			w.Header().Set(ujeebu.CreditsHeader, strconv.Itoa(credits))
+		}
+	}
+	for name, values := range rec.Header() {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(rec.Code)
+	_, _ = w.Write(rec.Body.Bytes())
+}
+
+// nextFault returns the fault to apply to a request, consuming it. The caller holds s.mu.
+func (s *Server) nextFault(endpoint string) *Fault {
+	for _, key := range []string{endpoint, ""} {
+		faults := s.faults[key]
+		if len(faults) == 0 {
+			continue
+		}
+		fault := *faults[0]
+		if faults[0].Times > 0 {
+			faults[0].Times--
+			if faults[0].Times == 0 {
+				s.faults[key] = faults[1:]
+			}
+		}
+		return &fault
+	}
+	return nil
+}
+
+// account returns the account details. The caller holds s.mu.
+func (s *Server) account() ujeebu.AccountResponse {
+	next := time.Now().AddDate(0, 0, 12).Format("2006-01-02")
+	quota := s.balance + s.used
+	used := 0.0
+	if quota > 0 {
+		used = float64(s.used) * 100 / float64(quota)
+	}
+	return ujeebu.AccountResponse{
+		Balance:             s.balance,
+		DaysTillNextBilling: 12,
+		NextBillingDate:     &next,
+		Plan:                "TEST",
+		Quota:               quota,
+		ConcurrentRequests:  10,
+		TotalRequests:       len(s.requests),
+		RequestPerSecond:    10,
+		Used:                s.used,
+		UsedPercent:         used,
+		UserID:              "ujeebutest",
+	}
+}
+
+// writeError writes fault as an APIError body
+func writeError(w http.ResponseWriter, r *http.Request, fault Fault) {
+	message := fault.Message
+	if message == "" {
+		message = http.StatusText(fault.StatusCode)
+	}
+	code := fault.ErrorCode
+	if code == nil {
+		code = strconv.Itoa(fault.StatusCode)
+	}
+	if fault.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int((fault.RetryAfter+time.Second-1)/time.Second)))
+	}
+	writeJSON(w, fault.StatusCode, ujeebu.APIError{
+		URL:       requestParam(r, "url"),
+		Message:   message,
+		ErrorCode: code,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// requestParam returns a parameter of r from its query string or JSON body
+func requestParam(r *http.Request, name string) string {
+	var body []byte
+	if r.Body != nil {
+		body, _ = io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	return Request{Query: r.URL.Query(), Body: body}.Param(name)
+}
@@ -0,0 +1,192 @@
+package ujeebutest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ujeebu/ujeebu-go"
+)
+
+func TestServer_Endpoints(t *testing.T) {
+	srv := NewServer(t)
+	client := srv.Client()
+
+	article, credits, err := client.Extract(ujeebu.ExtractParams{URL: "https://example.com/hello-world"})
+	require.NoError(t, err)
+	assert.Equal(t, "hello world | example.com", article.Title)
+	assert.Equal(t, DefaultCredits["extract"], credits)
+
+	card, credits, err := client.Card(ujeebu.CardParams{URL: "https://example.com/hello-world"})
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", card.SiteName)
+	assert.Equal(t, DefaultCredits["card"], credits)
+
+	html, _, err := client.HTML(ujeebu.ScrapeParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.Contains(t, html, "<h1>home | example.com</h1>")
+
+	raw, _, err := client.ScrapeWithContext(context.Background(), ujeebu.ScrapeParams{URL: "https://example.com", ResponseType: "pdf"})
+	require.NoError(t, err)
+	assert.Equal(t, PDF, raw.Body)
+	assert.Equal(t, "application/pdf", raw.ContentType())
+
+	shot, _, err := client.Screenshot(ujeebu.ScrapeParams{URL: "https://example.com"}, true, "")
+	require.NoError(t, err)
+	assert.NotEmpty(t, shot)
+
+	rules, err := ujeebu.NewRules().Text("title", "h1").Link("links", "a").Multiple().Build()
+	require.NoError(t, err)
+	scraped, _, err := client.Scrape(ujeebu.ScrapeParams{URL: "https://example.com", ExtractRules: rules})
+	require.NoError(t, err)
+	result := scraped.Result.(map[string]any)
+	assert.Equal(t, "title of home | example.com", result["title"])
+	assert.Len(t, result["links"], 2)
+
+	search, credits, err := client.GoogleSearch(ujeebu.SerpParams{Search: "golang", ResultsCount: 5, Page: 2})
+	require.NoError(t, err)
+	require.Len(t, search.OrganicResults, 5)
+	assert.Equal(t, 6, search.OrganicResults[0].Position)
+	assert.Contains(t, search.Pagination.Api.Next, "page=3")
+	assert.Equal(t, DefaultCredits["serp"], credits)
+
+	news, _, err := client.GoogleNewsSearch(ujeebu.SerpParams{Search: "golang"})
+	require.NoError(t, err)
+	assert.Len(t, news.News, 10)
+
+	account, err := client.Account()
+	require.NoError(t, err)
+	spent := DefaultCredits["extract"] + DefaultCredits["card"] + 4*DefaultCredits["scrape"] + 2*DefaultCredits["serp"]
+	assert.Equal(t, DefaultBalance-spent, account.Balance)
+	assert.Equal(t, spent, account.Used)
+}
+
+func TestServer_Validation(t *testing.T) {
+	srv := NewServer(t)
+	client := srv.Client()
+
+	_, err := client.Account()
+	require.NoError(t, err)
+
+	srv.SetAPIKey("the-right-key")
+	_, err = client.Account()
+	var apiErr *ujeebu.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+
+	srv.SetAPIKey("")
+	_, _, err = client.Extract(ujeebu.ExtractParams{URL: "not-a-url"})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "INVALID_URL", apiErr.ErrorCode)
+}
+
+func TestServer_Faults(t *testing.T) {
+	srv := NewServer(t)
+	srv.Fail("extract", Fault{StatusCode: http.StatusTooManyRequests, Times: 2})
+	client := srv.Client(ujeebu.WithRetry(3, time.Millisecond, 5*time.Millisecond))
+
+	_, meta, err := client.ExtractWithMeta(context.Background(), ujeebu.ExtractParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, meta.Attempts)
+	assert.Equal(t, DefaultCredits["extract"], meta.Credits, "failed attempts are not charged")
+
+	// Faults without an endpoint apply to all of them
+	srv.Fail("", Fault{StatusCode: http.StatusServiceUnavailable, Message: "maintenance", Times: 1})
+	_, _, err = client.Card(ujeebu.CardParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	srv.AssertCalls(t, "card", 2)
+
+	srv.Fail("serp", Fault{StatusCode: http.StatusNotFound, ErrorCode: "NOT_FOUND"})
+	for i := 0; i < 2; i++ {
+		_, _, err = client.Serp(ujeebu.SerpParams{Search: "golang"})
+		var apiErr *ujeebu.APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, "NOT_FOUND", apiErr.ErrorCode)
+	}
+
+	srv.Fail("account", Fault{StatusCode: http.StatusTooManyRequests, RetryAfter: 1500 * time.Millisecond})
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/account", nil)
+	require.NoError(t, err)
+	req.Header.Set("ApiKey", TestAPIKey)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("Retry-After"))
+}
+
+func TestServer_BalanceAndLatency(t *testing.T) {
+	srv := NewServer(t)
+	srv.SetCredits("serp", 30)
+	srv.SetBalance(50)
+	client := srv.Client()
+
+	_, credits, err := client.Serp(ujeebu.SerpParams{Search: "golang"})
+	require.NoError(t, err)
+	assert.Equal(t, 30, credits)
+
+	_, _, err = client.Serp(ujeebu.SerpParams{Search: "golang"})
+	var apiErr *ujeebu.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusPaymentRequired, apiErr.StatusCode)
+
+	srv.SetLatency("", time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.AccountWithContext(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestServer_RequestAssertions(t *testing.T) {
+	srv := NewServer(t)
+	srv.Handle("card", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, ujeebu.CardResponse{Title: "Custom"})
+	})
+	client := srv.Client()
+
+	card, _, err := client.Card(ujeebu.CardParams{URL: "https://example.com", JS: true, CustomHeaders: map[string]string{"Cookie": "a=b"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Custom", card.Title)
+
+	_, _, err = client.Extract(ujeebu.ExtractParams{URL: "https://example.com", RawHTML: "<p>hi</p>"})
+	require.NoError(t, err)
+
+	srv.AssertCalls(t, "card", 1)
+	srv.AssertParam(t, "card", "js", "true")
+	srv.AssertParam(t, "extract", "raw_html", "<p>hi</p>")
+
+	r, ok := srv.LastRequest("card")
+	require.True(t, ok)
+	assert.Equal(t, "a=b", r.Header.Get("UJB-Cookie"))
+	assert.Equal(t, TestAPIKey, r.Header.Get("ApiKey"))
+	assert.Len(t, srv.Requests(""), 2)
+
+	tb := &recordingTB{TB: t}
+	assert.False(t, srv.AssertCalls(tb, "serp", 1))
+	assert.False(t, srv.AssertParam(tb, "card", "js", "false"))
+	assert.Len(t, tb.errors, 2)
+
+	srv.Reset()
+	assert.Empty(t, srv.Requests(""))
+	card, _, err = client.Card(ujeebu.CardParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", card.URL)
+}
+
+// recordingTB records the errors reported by assertions instead of failing the test
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (tb *recordingTB) Helper() {}
+
+func (tb *recordingTB) Errorf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}